@@ -0,0 +1,121 @@
+package clickhouse
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// 列对齐方式
+const (
+	alignLeft  = "left"
+	alignRight = "right"
+)
+
+// setAlign 处理 \align col:left|right[,col:left|right...] 和 \align reset
+func (c *CLI) setAlign(args string) {
+	switch strings.ToLower(args) {
+	case "":
+		if len(c.align) == 0 {
+			fmt.Fprintf(c.term, "No column alignment overrides.\n")
+			return
+		}
+		cols := make([]string, 0, len(c.align))
+		for col := range c.align {
+			cols = append(cols, col)
+		}
+		sort.Strings(cols)
+		for i, col := range cols {
+			cols[i] = col + ":" + c.align[col]
+		}
+		fmt.Fprintf(c.term, "Column alignment: %s\n", strings.Join(cols, ","))
+		return
+	case "reset":
+		c.align = nil
+		fmt.Fprintf(c.term, "Column alignment overrides cleared.\n")
+		return
+	}
+
+	overrides := make(map[string]string)
+	for _, item := range strings.Split(args, ",") {
+		col, side, ok := strings.Cut(strings.TrimSpace(item), ":")
+		side = strings.ToLower(strings.TrimSpace(side))
+		if !ok || col == "" || (side != alignLeft && side != alignRight) {
+			fmt.Fprintf(c.term, "Usage: \\align col:left|right[,col:left|right...] | reset\n")
+			return
+		}
+		overrides[strings.TrimSpace(col)] = side
+	}
+
+	if c.align == nil {
+		c.align = make(map[string]string)
+	}
+	for col, side := range overrides {
+		c.align[col] = side
+	}
+	fmt.Fprintf(c.term, "Alignment set for %d column(s).\n", len(overrides))
+}
+
+// setShowTypes 处理 \types [on|off]：是否在表头列名下方显示列类型
+func (c *CLI) setShowTypes(args string) {
+	switch strings.ToLower(args) {
+	case "":
+		c.showTypes = !c.showTypes
+	case "on":
+		c.showTypes = true
+	case "off":
+		c.showTypes = false
+	default:
+		fmt.Fprintf(c.term, "Usage: \\types [on|off]\n")
+		return
+	}
+	if c.showTypes {
+		fmt.Fprintf(c.term, "Column types shown in table headers.\n")
+	} else {
+		fmt.Fprintf(c.term, "Column types hidden.\n")
+	}
+}
+
+// headerTypes 返回表头中显示的列类型，未开启 \types 时返回 nil
+func (c *CLI) headerTypes(types []string) []string {
+	if !c.showTypes {
+		return nil
+	}
+	return types
+}
+
+// columnAlign 返回各列是否右对齐：数值类型的列默认右对齐，其余左对齐，\align 的覆盖优先
+func (c *CLI) columnAlign(cols, types []string) []bool {
+	right := make([]bool, len(cols))
+	for i, col := range cols {
+		switch c.align[col] {
+		case alignRight:
+			right[i] = true
+		case alignLeft:
+		default:
+			right[i] = i < len(types) && isNumericType(baseType(types[i]))
+		}
+	}
+	return right
+}
+
+// isNumericType 判断是否是整数、浮点或 Decimal 类型
+func isNumericType(typ string) bool {
+	if strings.HasPrefix(typ, "Interval") {
+		return false
+	}
+	for _, prefix := range []string{"Int", "UInt", "Float", "Decimal"} {
+		if strings.HasPrefix(typ, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// padCell 按列宽填充单元格，right 为 true 时右对齐
+func padCell(s string, width int, right bool) string {
+	if right {
+		return fmt.Sprintf("%*s", width, s)
+	}
+	return fmt.Sprintf("%-*s", width, s)
+}
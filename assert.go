@@ -0,0 +1,134 @@
+package clickhouse
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// assertResult 处理 \assert [--save] <path>：将上一次查询结果与黄金文件比较，或保存为新的黄金文件
+// 结果以规范化的 TSV 文本比较（表头、类型、每行一条），与显示设置无关
+func (c *CLI) assertResult(args string) {
+	save := false
+	if rest, ok := strings.CutPrefix(args, "--save"); ok {
+		save = true
+		args = strings.TrimSpace(rest)
+	}
+	if args == "" {
+		fmt.Fprintf(c.term, "Usage: \\assert [--save] <path>\n")
+		return
+	}
+	if c.lastResult == nil {
+		fmt.Fprintf(c.term, "No result to assert. Run a query first.\n")
+		return
+	}
+
+	path := expandHome(args)
+	actual := canonicalResult(c.lastResult)
+
+	if save {
+		if err := os.WriteFile(path, []byte(actual), 0o644); err != nil {
+			fmt.Fprintf(c.term, "Cannot write golden file: %v\n", err)
+			return
+		}
+		fmt.Fprintf(c.term, "Saved %d rows to %s.\n\n", len(c.lastResult.rows), path)
+		return
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		c.failedAsserts++
+		fmt.Fprintf(c.term, "ASSERT FAILED: cannot read golden file: %v\n\n", err)
+		return
+	}
+	expected := string(data)
+	if expected == actual {
+		fmt.Fprintf(c.term, "ASSERT PASSED: result matches %s (%d rows).\n\n", path, len(c.lastResult.rows))
+		return
+	}
+
+	c.failedAsserts++
+	fmt.Fprintf(c.term, "ASSERT FAILED: result differs from %s\n", path)
+	c.printGoldenDiff(expected, actual)
+	fmt.Fprintf(c.term, "\n")
+}
+
+// AssertFailures 返回 \assert 失败的次数，批处理模式可据此设置退出码
+func (c *CLI) AssertFailures() int {
+	return c.failedAsserts
+}
+
+// printGoldenDiff 输出黄金文件缺少（-）和多出（+）的行；行集合相同时说明只是顺序不同
+func (c *CLI) printGoldenDiff(expected, actual string) {
+	expectedLines := strings.Split(strings.TrimSuffix(expected, "\n"), "\n")
+	actualLines := strings.Split(strings.TrimSuffix(actual, "\n"), "\n")
+
+	counts := make(map[string]int)
+	for _, line := range expectedLines {
+		counts[line]++
+	}
+	var added []string
+	for _, line := range actualLines {
+		if counts[line] > 0 {
+			counts[line]--
+		} else {
+			added = append(added, line)
+		}
+	}
+	var removed []string
+	for _, line := range expectedLines {
+		if counts[line] > 0 {
+			counts[line]--
+			removed = append(removed, line)
+		}
+	}
+
+	if len(added) == 0 && len(removed) == 0 {
+		fmt.Fprintf(c.term, "Same rows in a different order. Add ORDER BY to the query for a stable result.\n")
+		return
+	}
+	fmt.Fprintf(c.term, "%d expected lines missing, %d unexpected lines:\n", len(removed), len(added))
+	c.printDiffLines("- ", removed)
+	c.printDiffLines("+ ", added)
+}
+
+// canonicalResult 将结果序列化为规范文本：第一行列名、第二行类型，之后每行一条记录，
+// 单元格按 TSV 转义，NULL 写为 \N，时间统一为 ISO 格式
+func canonicalResult(rs *resultSet) string {
+	var b strings.Builder
+	b.WriteString(strings.Join(rs.cols, "\t") + "\n")
+	types := make([]string, len(rs.cols))
+	for i := range types {
+		types[i] = rs.typeOf(i)
+	}
+	b.WriteString(strings.Join(types, "\t") + "\n")
+
+	for _, row := range rs.rows {
+		cells := make([]string, len(row))
+		for i, v := range row {
+			cells[i] = canonicalCell(v, rs.typeOf(i))
+		}
+		b.WriteString(strings.Join(cells, "\t") + "\n")
+	}
+	return b.String()
+}
+
+// canonicalCell 返回单元格的规范文本
+func canonicalCell(v interface{}, typ string) string {
+	switch val := v.(type) {
+	case nil:
+		return `\N`
+	case time.Time:
+		return isoTime(val.UTC(), baseType(typ))
+	}
+	return escapeTSV(formatValue(v))
+}
+
+// escapeTSV 按 TSV 规则转义制表符、换行和反斜杠
+func escapeTSV(s string) string {
+	if !strings.ContainsAny(s, "\t\n\r\\") {
+		return s
+	}
+	return strings.NewReplacer(`\`, `\\`, "\t", `\t`, "\n", `\n`, "\r", `\r`).Replace(s)
+}
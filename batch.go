@@ -0,0 +1,120 @@
+package clickhouse
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/signal"
+	"strings"
+	"sync/atomic"
+)
+
+// errBatchAborted 批处理中语句被取消后选择了中止
+var errBatchAborted = errors.New("batch aborted")
+
+// runBatchStatement 执行批处理中的一条语句。Ctrl-C 只取消当前语句，交互模式下
+// 随后询问跳过、重试还是中止，非交互模式（RunScript 等）无人回答，直接中止；返回 false 表示中止整个批处理
+func (c *CLI) runBatchStatement(stmt string) bool {
+	for {
+		if !c.runInterruptible(func() { c.runStatement(stmt) }) {
+			return true
+		}
+		if !c.interactive {
+			return false
+		}
+
+		c.reader.SetPrompt("Statement cancelled. [s]kip to next, [a]bort batch, [r]etry? [S/a/r] ")
+		answer, err := c.reader.ReadLine()
+		if err != nil {
+			return false
+		}
+		switch strings.ToLower(strings.TrimSpace(answer)) {
+		case "a", "abort":
+			return false
+		case "r", "retry":
+			continue
+		default:
+			// 用户选择跳过，被取消的语句不算失败，\i 和 StopOnError 不会因此停止
+			c.lastErr = nil
+			return true
+		}
+	}
+}
+
+// runInterruptible 执行 fn，期间的 Ctrl-C 会取消当前语句的上下文而不是退出程序，返回是否被中断；
+// 已有父上下文（如 RunScript 传入的 ctx）时在其基础上派生，父上下文取消同样会中止语句。
+// Ctrl-C 来自 Interrupt（包括终端输入中的 Ctrl-C 字节）；终端是标准输入时还监听 SIGINT。
+// 嵌套调用时（如 \i 中的语句）只取消最内层，外层不算被中断
+func (c *CLI) runInterruptible(fn func()) bool {
+	parent := context.Background()
+	if c.stmtCtx != nil {
+		parent = c.stmtCtx
+	}
+	ctx, cancel := context.WithCancel(parent)
+	defer cancel()
+
+	var interrupted atomic.Bool
+	c.interruptMu.Lock()
+	outer := c.interruptFn
+	c.interruptFn = func() {
+		interrupted.Store(true)
+		cancel()
+	}
+	c.interruptMu.Unlock()
+	defer func() {
+		c.interruptMu.Lock()
+		c.interruptFn = outer
+		c.interruptMu.Unlock()
+	}()
+
+	// 本地终端执行语句时不在 raw 模式，Ctrl-C 以 SIGINT 送达；只在最外层监听，由 Interrupt 交给最内层
+	if outer == nil && c.stdinTerminal() {
+		sigCh := make(chan os.Signal, 1)
+		signal.Notify(sigCh, os.Interrupt)
+		defer signal.Stop(sigCh)
+
+		done := make(chan struct{})
+		defer close(done)
+		go func() {
+			for {
+				select {
+				case <-sigCh:
+					c.Interrupt()
+				case <-done:
+					return
+				}
+			}
+		}()
+	}
+
+	prev := c.stmtCtx
+	c.stmtCtx = ctx
+	defer func() { c.stmtCtx = prev }()
+
+	fn()
+	return interrupted.Load()
+}
+
+// Interrupt 取消正在执行的语句，效果与 Ctrl-C 相同，返回是否有语句被取消；可以从其他 goroutine 调用。
+// 终端输入中的 Ctrl-C 会自动调用它，自定义 Terminal 从别处收到中断请求（如 SSH 的 signal 请求）时也可以调用
+func (c *CLI) Interrupt() bool {
+	c.interruptMu.Lock()
+	fn := c.interruptFn
+	c.interruptMu.Unlock()
+	if fn == nil {
+		return false
+	}
+	fn()
+	return true
+}
+
+// stdinTerminal 终端是否是进程的标准输入，此时 Ctrl-C 由操作系统以 SIGINT 送达
+func (c *CLI) stdinTerminal() bool {
+	f, ok := c.term.(*os.File)
+	return ok && f.Fd() == os.Stdin.Fd()
+}
+
+// cancelled 判断当前语句是否已被 Ctrl-C 取消
+func (c *CLI) cancelled() bool {
+	return c.stmtCtx != nil && c.stmtCtx.Err() != nil
+}
@@ -0,0 +1,72 @@
+package clickhouse
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strings"
+)
+
+// checksumQuery 计算查询结果的校验和与行数，用于比较不同环境的数据是否一致
+// 优先在服务端计算 sum(sipHash64(*))；服务端无法计算时读取全部结果在客户端计算
+// 两种方式的结果不可互相比较，输出中会注明所用方法
+func (c *CLI) checksumQuery(query string) {
+	query = strings.TrimSuffix(strings.TrimSpace(query), ";")
+	if query == "" {
+		fmt.Fprintf(c.term, "Usage: \\checksum <query>\n")
+		return
+	}
+
+	ctx, cancel := c.queryContext()
+	defer cancel()
+
+	var (
+		rows uint64
+		sum  uint64
+	)
+	err := c.db.QueryRowContext(ctx, "SELECT count(), sum(sipHash64(*)) FROM ("+query+")").Scan(&rows, &sum)
+	if err == nil {
+		fmt.Fprintf(c.term, "Checksum: %016x  Rows: %d  (server-side sum of sipHash64)\n\n", sum, rows)
+		return
+	}
+
+	fmt.Fprintf(c.term, "Server-side checksum failed (%v), computing client-side.\n", err)
+	rows, sum, err = c.clientChecksum(query)
+	if err != nil {
+		c.printError(err)
+		return
+	}
+	fmt.Fprintf(c.term, "Checksum: %016x  Rows: %d  (client-side sum of FNV-1a)\n\n", sum, rows)
+}
+
+// clientChecksum 读取全部结果（不受 maxRows 限制），按行计算 FNV-1a 哈希后求和，与行顺序无关
+func (c *CLI) clientChecksum(query string) (uint64, uint64, error) {
+	ctx, cancel := c.queryContext()
+	defer cancel()
+
+	rows, err := c.db.QueryContext(ctx, query)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer rows.Close()
+
+	cols, err := rows.Columns()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var count, sum uint64
+	for rows.Next() {
+		vals, err := scanRow(rows, len(cols))
+		if err != nil {
+			return 0, 0, err
+		}
+		h := fnv.New64a()
+		for _, v := range vals {
+			// 用 \x00 分隔单元格，避免 ("ab","c") 与 ("a","bc") 得到相同哈希
+			fmt.Fprintf(h, "%s\x00", formatValue(v))
+		}
+		sum += h.Sum64()
+		count++
+	}
+	return count, sum, rows.Err()
+}
@@ -1,15 +1,24 @@
 package clickhouse
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"io"
+	"net"
+	"os"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
+	"unicode/utf8"
 
-	_ "github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/chzyer/readline"
+	"github.com/google/uuid"
 )
 
 // Terminal 终端接口，用于输入输出
@@ -21,8 +30,14 @@ type Terminal interface {
 // CLI ClickHouse 交互式命令行客户端
 type CLI struct {
 	term          Terminal
+	config        *Config // 连接配置：超时、TLS、压缩、连接池和额外参数
 	host          string
 	port          int
+	socket        string        // Unix 域套接字路径，非空时代替 host:port 连接
+	protocol      string        // 连接协议: native 或 http
+	hosts         []string      // Config.Hosts 配置的多个主机，为空时只连接 host:port
+	dialedAddr    atomic.Value  // 多主机时最近一次成功建立连接的地址
+	waitServer    time.Duration // Connect 时等待服务端可达的最长时间
 	username      string
 	password      string
 	database      string
@@ -31,14 +46,76 @@ type CLI struct {
 	serverInfo    ServerInfo
 	timingEnabled bool
 	verticalMode  bool
-	maxRows       int
+	transposeMode bool
+	format        string            // \format 选择的输出格式，见 outputFormats
+	maxRows       int               // 查询结果最多读取并显示的行数，0 表示不限制
+	nullValue     string            // NULL 的显示文本
+	queryTimeout  time.Duration     // 单条语句的客户端超时，0 表示不限制
+	initFile      string            // 启动脚本路径，为空时使用 ~/.clickhouse-cli.rc
+	historyFile   string            // 历史记录文件，为空时使用 ~/.clickhouse-cli-history
+	historyLoaded bool              // 历史记录是否已载入
+	cluster       string            // 会话默认集群，用于生成 ON CLUSTER 子句
+	expandEnv     bool              // 是否替换 SQL 中的 ${VAR}
+	datetimeMode  string            // 日期时间显示方式: iso, epoch, epoch_ms
+	memWarn       int64             // 查询峰值内存告警阈值（字节），0 表示关闭
+	collapse      bool              // 是否折叠连续重复的分组列值
+	collapseCols  []string          // 折叠的列，为空时折叠第一列
+	output        string            // 交互输出模式: text, json
+	pager         string            // 分页器命令，为空时不使用分页器
+	pagerAuto     bool              // 只在结果超过一屏时使用分页器
+	stats         *readStats        // 当前语句的服务端读取统计
+	disconnected  bool              // 最近一次执行是否因连接断开而失败
+	pendingSQL    string            // 因断线失败、等待 \reconnect 重放的语句
+	lastErr       error             // 最近一次 printError 输出的错误，用于判断语句是否成功
+	queryLog      *os.File          // \querylog 审计文件
+	allowTx       bool              // 是否将 BEGIN/COMMIT/ROLLBACK 发送到服务端
+	stopOnError   bool              // 第一个失败的语句是否结束 Start/RunScript 并返回其错误
+	inTransaction bool              // 服务端事务是否已由 BEGIN 开启，显示在提示符中
+	failedAsserts int               // \assert 失败次数
+	busyRetries   int               // TOO_MANY_SIMULTANEOUS_QUERIES 的最大重试次数
+	busyDelay     time.Duration     // 首次重试前的等待时间
+	settings      map[string]string // 本会话 SET 的设置，随每条查询发送
+	schema        *schemaCache      // 按库懒加载的表结构缓存
+	page          *pageState        // \next / \prev 分页状态
+	lastRowCount  int               // 最近一次查询显示的行数
+	lastResult    *resultSet        // 最近一次查询结果
+	diffBase      *resultSet        // \diff-result 保存的对比基准
+	grep          *rowFilter        // \grep 设置的下一次查询的行过滤
+	lastQueryID   string            // 最近一次执行语句的 query_id
+	prevQueryID   string            // 上一次执行语句的 query_id
+	stmtCtx       context.Context   // 当前语句的父上下文，Ctrl-C 时取消
+	interruptMu   sync.Mutex        // 保护 interruptFn
+	interruptFn   func()            // 最内层 runInterruptible 的取消函数，Interrupt 调用它
+	stmtArgs      []interface{}     // ExecParams 传入的绑定参数，随当前语句交给驱动
+	queryParams   map[string]string // \param 设置的 {name:Type} 查询参数
+	inScript      bool              // 是否正在执行脚本
+	interactive   bool              // 是否在 Start 的交互循环中，只有此时才能向用户提问
+	interrupted   bool              // 空闲提示符下刚按过 Ctrl-C，再按一次退出
+	importErrors  string            // 导入时坏行的处理方式: abort, skip, report
+	rejectPath    string            // report 模式下被拒绝行的写入文件
+	autoLimit     int               // \limit 为交互式 SELECT 追加的 LIMIT，0 表示关闭
+	limitWarned   bool              // 是否已提示过自动追加 LIMIT
+	limitAdded    bool              // 当前语句的 LIMIT 是否由 \limit 自动追加，此时不作为可分页查询
+	normalized    bool              // 查询后是否显示服务端规范化后的语句
+	align         map[string]string // \align 设置的列对齐覆盖: 列名 -> left/right
+	showTypes     bool              // 是否在表头列名下方显示列类型
+	groupDigits   bool              // 是否为数值列插入千位分隔符
+	digitSep      string            // 千位分隔符，默认为逗号
+	color         string            // 颜色模式: on, off, auto
+	widthLimit    int               // 表格列宽上限，0 表示不限制
+	wrapCells     bool              // 超出列宽的值折行显示而不是截断
+	render        string            // 结果渲染方式: client, server
+	httpPort      int               // server 渲染使用的 HTTP 接口端口
+
+	// RegisterFormatter 注册的自定义输出格式
+	formatters map[string]OutputFormatter
 }
 
 // ServerInfo ClickHouse 服务器信息
 type ServerInfo struct {
-	Version    string
-	Uptime     int64
-	BuildType  string
+	Version   string
+	Uptime    int64
+	BuildType string
 }
 
 // Config ClickHouse 连接配置
@@ -46,8 +123,14 @@ type Config struct {
 	Host            string
 	Port            int
 	Username        string
-	Password        string
+	Password        string // 为空时使用 CLICKHOUSE_PASSWORD 环境变量，认证失败时在终端中询问
 	Database        string
+	Socket          string        // Unix 域套接字路径，设置后通过套接字连接并忽略 Host/Port
+	Protocol        string        // 连接协议: native（默认，端口 9000/9440）或 http（HTTP 接口，端口 8123/8443）
+	Hosts           []string      // 多个 host[:port]，设置后代替 Host，按 HostOrder 依次尝试直到连通
+	HostOrder       string        // 多主机的连接顺序: in_order（默认）或 round_robin
+	WaitForServer   time.Duration // Connect 时重试直到服务端可达的最长时间，0 表示不等待
+	HTTPPort        int           // HTTP 接口端口，\render server 使用，默认 8123
 	Secure          bool          // 使用 TLS
 	SkipVerify      bool          // 跳过 TLS 验证
 	DialTimeout     time.Duration // 连接超时
@@ -57,64 +140,222 @@ type Config struct {
 	MaxIdleConns    int           // 最大空闲连接数
 	ConnMaxLifetime time.Duration // 连接最大生命周期
 	Compression     string        // 压缩方式: lz4, zstd, none
+	InitFile        string        // 启动脚本，默认 ~/.clickhouse-cli.rc
+	HistoryFile     string        // 历史记录文件，默认 ~/.clickhouse-cli-history
+	OutputFormat    string        // 交互输出模式: text（默认）或 json，也可以是 \format 的格式如 Pretty、Vertical、CSV、Markdown
+	BusyRetries     int           // 服务端繁忙 (code 202) 时的重试次数，默认 3，负数表示不重试
+	BusyRetryDelay  time.Duration // 首次重试前的等待时间，之后按次数线性增加，默认 1s
+	QueryTimeout    time.Duration // 单条语句的客户端超时，默认 60s，负数表示不限制
+	MaxRows         int           // 查询结果最多显示的行数，默认 1000，负数表示不限制
+	NullValue       string        // NULL 的显示文本，默认 ᴺᵁᴸᴸ
+	DigitSeparator  string        // \pretty numbers 使用的千位分隔符，默认为逗号
+	Color           string        // 表格颜色: on, off 或 auto（默认，输出不是终端或设置了 NO_COLOR 时关闭）
+	Prompt          string        // 提示符模板，支持 {host} {port} {user} {db} {tx}，默认 "{tx}{host} :) "
+	SchemaExclude   []string      // 不加载字段元数据的数据库，默认 system 和 INFORMATION_SCHEMA
+	// 服务端开启了实验性事务时设为 true，BEGIN/COMMIT/ROLLBACK 直接发送到服务端
+	AllowTransactions bool
+	// 第一个失败的语句即停止 Start 和 RunScript 并返回其错误，便于脚本和守护进程据此设置退出码
+	StopOnError bool
 	// 其他参数
 	Params map[string]string
 }
 
 // NewCLI 创建新的 ClickHouse CLI 实例
 func NewCLI(term Terminal, host string, port int, username, password, database string) *CLI {
-	return &CLI{
-		term:     term,
-		host:     host,
-		port:     port,
-		username: username,
-		password: password,
-		database: database,
-		reader:   NewReader(term),
-		maxRows:  1000,
+	c := &CLI{
+		term:         term,
+		config:       &Config{Host: host, Port: port, Username: username, Password: password, Database: database},
+		host:         host,
+		port:         port,
+		username:     username,
+		password:     password,
+		database:     database,
+		reader:       NewReader(term),
+		maxRows:      defaultMaxRows,
+		queryTimeout: defaultQueryTimeout,
+		nullValue:    defaultNullValue,
+		digitSep:     defaultDigitSeparator,
+		color:        colorAuto,
+		widthLimit:   defaultWidthLimit,
+		format:       formatPrettyCompact,
+		datetimeMode: datetimeISO,
+		importErrors: importAbort,
+		render:       renderClient,
+		httpPort:     defaultHTTPPort,
+		output:       outputText,
+		busyRetries:  defaultBusyRetries,
+		busyDelay:    defaultBusyRetryDelay,
+		settings:     make(map[string]string),
+		schema:       newSchemaCache(nil),
 	}
+	c.reader.SetCompleter(&completer{cli: c})
+	c.reader.SetInterrupt(c.Interrupt)
+	return c
 }
 
 // NewCLIWithConfig 使用配置创建 ClickHouse CLI 实例
 func NewCLIWithConfig(term Terminal, config *Config) *CLI {
-	return &CLI{
-		term:     term,
-		host:     config.Host,
-		port:     config.Port,
-		username: config.Username,
-		password: config.Password,
-		database: config.Database,
-		reader:   NewReader(term),
-		maxRows:  1000,
+	c := &CLI{
+		term:         term,
+		config:       config,
+		host:         config.Host,
+		port:         config.Port,
+		socket:       config.Socket,
+		protocol:     strings.ToLower(config.Protocol),
+		hosts:        config.Hosts,
+		waitServer:   config.WaitForServer,
+		username:     config.Username,
+		password:     config.Password,
+		database:     config.Database,
+		reader:       NewReader(term),
+		maxRows:      defaultMaxRows,
+		queryTimeout: defaultQueryTimeout,
+		nullValue:    defaultNullValue,
+		digitSep:     defaultDigitSeparator,
+		color:        colorAuto,
+		widthLimit:   defaultWidthLimit,
+		format:       formatPrettyCompact,
+		datetimeMode: datetimeISO,
+		importErrors: importAbort,
+		render:       renderClient,
+		httpPort:     defaultHTTPPort,
+		initFile:     config.InitFile,
+		historyFile:  config.HistoryFile,
+		allowTx:      config.AllowTransactions,
+		stopOnError:  config.StopOnError,
+		output:       outputText,
+		busyRetries:  defaultBusyRetries,
+		busyDelay:    defaultBusyRetryDelay,
+		settings:     make(map[string]string),
+		schema:       newSchemaCache(config.SchemaExclude),
 	}
+	if len(c.hosts) == 1 || (len(c.hosts) > 0 && c.host == "") {
+		// 第一个主机也作为 host:port，供 HTTP 接口和单主机时使用
+		c.host = c.hosts[0]
+		if h, p, err := net.SplitHostPort(c.host); err == nil {
+			c.host = h
+			if c.port == 0 || len(c.hosts) == 1 {
+				c.port, _ = strconv.Atoi(p)
+			}
+		}
+		if len(c.hosts) == 1 {
+			c.hosts = nil
+		}
+	}
+	if c.protocol == "" {
+		c.protocol = protocolNative
+	}
+	if c.port == 0 && c.socket == "" {
+		c.port = c.defaultPort()
+	}
+	if config.BusyRetries != 0 {
+		c.busyRetries = max(config.BusyRetries, 0)
+	}
+	if config.BusyRetryDelay > 0 {
+		c.busyDelay = config.BusyRetryDelay
+	}
+	if config.NullValue != "" {
+		c.nullValue = config.NullValue
+	}
+	if config.DigitSeparator != "" {
+		c.digitSep = config.DigitSeparator
+	}
+	switch mode := strings.ToLower(config.Color); mode {
+	case colorOn, colorOff:
+		c.color = mode
+	}
+	if config.MaxRows != 0 {
+		c.maxRows = max(config.MaxRows, 0)
+	}
+	if config.QueryTimeout != 0 {
+		c.queryTimeout = max(config.QueryTimeout, 0)
+	}
+	if config.HTTPPort > 0 {
+		c.httpPort = config.HTTPPort
+	} else if c.viaHTTP() {
+		// 通过 HTTP 接口连接时，server 渲染使用同一个端口
+		c.httpPort = c.port
+	} else if config.Secure {
+		c.httpPort = defaultHTTPSPort
+	}
+	if strings.EqualFold(config.OutputFormat, outputJSON) {
+		c.output = outputJSON
+	} else if f, ok := lookupName(config.OutputFormat, outputFormats); ok {
+		c.format = f
+	}
+	c.reader.SetCompleter(&completer{cli: c})
+	c.reader.SetInterrupt(c.Interrupt)
+	return c
 }
 
 // Connect 连接到 ClickHouse
 func (c *CLI) Connect() error {
-	dsn := fmt.Sprintf("clickhouse://%s:%s@%s:%d/%s?dial_timeout=10s&read_timeout=30s",
-		c.username, c.password, c.host, c.port, c.database)
-
-	var err error
-	c.db, err = sql.Open("clickhouse", dsn)
-	if err != nil {
+	if err := c.connect(c.canPrompt()); err != nil {
 		return err
 	}
 
-	c.db.SetMaxOpenConns(10)
-	c.db.SetMaxIdleConns(5)
-	c.db.SetConnMaxLifetime(time.Hour)
+	c.loadHistory()
+	c.showWelcome()
 
-	if err := c.db.Ping(); err != nil {
-		c.db.Close()
+	return nil
+}
+
+// connect 建立连接并获取服务端信息，不输出欢迎信息
+// 未配置密码时使用 CLICKHOUSE_PASSWORD；仍为空且 prompt 时先询问密码再连接，避免一次注定失败的认证
+func (c *CLI) connect(prompt bool) error {
+	if c.password == "" {
+		c.password = os.Getenv(passwordEnv)
+	}
+	if c.password == "" && prompt {
+		if err := c.askPassword(); err != nil {
+			return err
+		}
+	}
+	open := c.open
+	if c.waitServer > 0 {
+		open = func() error { return c.waitForServer(c.waitServer) }
+	}
+	if err := open(); err != nil {
 		return err
 	}
 
 	c.fetchServerInfo()
-	c.showWelcome()
+	c.schemaSettings()
+	return nil
+}
 
+// open 按当前连接参数和 Config 建立连接池并验证连通性
+func (c *CLI) open() error {
+	opts, err := c.connOptions()
+	if err != nil {
+		return err
+	}
+	db := clickhouse.OpenDB(opts)
+	c.applyPoolConfig(db)
+
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return c.explainConnectError(err)
+	}
+
+	c.db = db
 	return nil
 }
 
+// addr 返回当前连接地址：套接字路径、host:port，多主机时为实际连上的地址
+func (c *CLI) addr() string {
+	if c.socket != "" {
+		return c.socket
+	}
+	if c.multiHost() {
+		if addr := c.connectedAddr(); addr != "" {
+			return addr
+		}
+		return c.hostsDescription()
+	}
+	return fmt.Sprintf("%s:%d", c.host, c.port)
+}
+
 // fetchServerInfo 获取服务器信息
 func (c *CLI) fetchServerInfo() {
 	c.db.QueryRow("SELECT version()").Scan(&c.serverInfo.Version)
@@ -124,92 +365,138 @@ func (c *CLI) fetchServerInfo() {
 // showWelcome 显示欢迎信息
 func (c *CLI) showWelcome() {
 	fmt.Fprintf(c.term, "ClickHouse client version %s\n", c.serverInfo.Version)
-	fmt.Fprintf(c.term, "Connecting to %s:%d\n", c.host, c.port)
-	fmt.Fprintf(c.term, "Connected to ClickHouse server version %s\n", c.serverInfo.Version)
+	via := ""
+	if c.viaHTTP() {
+		via = " over HTTP"
+	}
+	if c.multiHost() {
+		fmt.Fprintf(c.term, "Connecting to one of %s%s\n", c.hostsDescription(), via)
+		fmt.Fprintf(c.term, "Connected to ClickHouse server version %s at %s\n", c.serverInfo.Version, c.addr())
+	} else {
+		fmt.Fprintf(c.term, "Connecting to %s%s\n", c.addr(), via)
+		fmt.Fprintf(c.term, "Connected to ClickHouse server version %s\n", c.serverInfo.Version)
+	}
 	fmt.Fprintf(c.term, "\n")
 }
 
-// Start 启动交互式命令行
+// Start 启动交互式命令行。输入结束（EOF）或 exit/quit/\q 时返回 nil；
+// Config.StopOnError 时启动脚本或输入中第一个失败的语句会结束循环并返回该错误，默认只输出错误并继续
 func (c *CLI) Start() error {
+	c.interactive = true
+	defer func() { c.interactive = false }()
+
+	if err := c.runInitFile(); err != nil {
+		return err
+	}
+
 	for {
 		// 设置提示符
 		prompt := c.getPrompt()
 		c.reader.SetPrompt(prompt)
 
-		sqlStr := c.readMultiLine()
+		sqlStr, quit := c.readMultiLine()
+		if quit {
+			fmt.Fprintf(c.term, "Bye\n")
+			return nil
+		}
 		if sqlStr == "" {
 			continue
 		}
 
-		sqlStr = strings.TrimSpace(sqlStr)
-
-		if c.handleSpecialCommand(sqlStr) {
-			if strings.ToLower(sqlStr) == "exit" || strings.ToLower(sqlStr) == "quit" {
+		// 一次输入可能包含多条以分号分隔的语句，依次执行；反斜杠命令同样可能查询服务端，一并可被 Ctrl-C 取消
+		for _, stmt := range splitStatements(sqlStr) {
+			c.lastErr = nil
+			var special bool
+			interrupted := c.runInterruptible(func() {
+				if special = c.handleSpecialCommand(stmt); !special {
+					c.executeSQL(stmt)
+				}
+			})
+			if special && isQuitCommand(stmt) {
 				return nil
 			}
-			continue
-		}
-
-		c.executeSQL(sqlStr)
-	}
-}
+			if interrupted {
+				// 执行期间 Ctrl-C 只取消当前语句，并跳过同一输入中剩余的语句
+				break
+			}
 
-// getPrompt 获取提示符
-func (c *CLI) getPrompt() string {
-	if c.database != "" {
-		return fmt.Sprintf("%s :) ", c.database)
+			if c.stopOnError && c.lastErr != nil {
+				return c.lastErr
+			}
+		}
 	}
-	return "clickhouse :) "
 }
 
-// readMultiLine 读取多行 SQL
-func (c *CLI) readMultiLine() string {
+// readMultiLine 读取多行 SQL，返回的 quit 表示用户要求退出（EOF 或空闲时连按两次 Ctrl-C）
+// Ctrl-C 丢弃已输入的内容；空闲提示符下第一次 Ctrl-C 只提示，第二次退出
+func (c *CLI) readMultiLine() (string, bool) {
 	var lines []string
 
 	for {
 		line, err := c.reader.ReadLine()
-		if err != nil {
-			if err == io.EOF {
-				return ""
+		if err == readline.ErrInterrupt {
+			if len(lines) > 0 || line != "" {
+				c.interrupted = false
+				return "", false
 			}
-			return ""
+			if c.interrupted {
+				return "", true
+			}
+			c.interrupted = true
+			fmt.Fprintf(c.term, "Press Ctrl-C again to exit.\n")
+			return "", false
 		}
+		if err != nil {
+			return "", err == io.EOF
+		}
+		c.interrupted = false
 
 		trimmed := strings.TrimSpace(line)
 		if trimmed == "" && len(lines) == 0 {
-			return ""
+			return "", false
 		}
 
 		// 如果是第一行，检查是否是特殊命令（不需要分隔符）
 		if len(lines) == 0 {
 			cmdLower := strings.ToLower(trimmed)
-			if cmdLower == "exit" || cmdLower == "quit" || cmdLower == "\\q" || 
-			   cmdLower == "help" || cmdLower == "\\h" || 
-			   cmdLower == "timing" || cmdLower == "\\timing" {
-				return trimmed
+			if cmdLower == "exit" || cmdLower == "quit" ||
+				cmdLower == "help" || cmdLower == "timing" ||
+				strings.HasPrefix(cmdLower, "source ") || strings.HasPrefix(cmdLower, "\\") {
+				c.addHistory(trimmed)
+				return trimmed, false
 			}
 		}
 
 		lines = append(lines, line)
 
-		if strings.HasSuffix(trimmed, ";") {
+		if statementComplete(strings.Join(lines, "\n")) {
 			break
 		}
 
 		// 设置多行提示符
-		c.reader.SetPrompt(":-] ")
+		c.reader.SetPrompt(c.continuationPrompt(strings.Join(lines, "\n")))
 	}
 
 	result := strings.Join(lines, "\n")
+	c.addHistory(result)
 	result = strings.TrimSuffix(strings.TrimSpace(result), ";")
-	return result
+	return result, false
+}
+
+// isQuitCommand 判断输入是否是退出命令 exit、quit 或 \q
+func isQuitCommand(cmd string) bool {
+	switch strings.ToLower(strings.TrimSpace(cmd)) {
+	case "exit", "quit", "\\q":
+		return true
+	}
+	return false
 }
 
 // handleSpecialCommand 处理特殊命令
 func (c *CLI) handleSpecialCommand(cmd string) bool {
 	cmdLower := strings.ToLower(strings.TrimSpace(cmd))
 
-	if cmdLower == "exit" || cmdLower == "quit" || cmdLower == "\\q" {
+	if isQuitCommand(cmd) {
 		fmt.Fprintf(c.term, "Bye\n")
 		return true
 	}
@@ -219,6 +506,11 @@ func (c *CLI) handleSpecialCommand(cmd string) bool {
 		return true
 	}
 
+	if topic, ok := strings.CutPrefix(strings.TrimSpace(cmd), "\\h "); ok {
+		c.showCommandHelp(strings.TrimSpace(topic))
+		return true
+	}
+
 	if cmdLower == "timing" || cmdLower == "\\timing" {
 		c.timingEnabled = !c.timingEnabled
 		if c.timingEnabled {
@@ -232,6 +524,7 @@ func (c *CLI) handleSpecialCommand(cmd string) bool {
 	if cmdLower == "vertical" || cmdLower == "\\G" {
 		c.verticalMode = !c.verticalMode
 		if c.verticalMode {
+			c.transposeMode = false
 			fmt.Fprintf(c.term, "Vertical output mode enabled.\n")
 		} else {
 			fmt.Fprintf(c.term, "Vertical output mode disabled.\n")
@@ -239,16 +532,29 @@ func (c *CLI) handleSpecialCommand(cmd string) bool {
 		return true
 	}
 
+	if !c.allowTx && isTransactionStatement(cmdLower) {
+		c.explainTransactions(cmd)
+		return true
+	}
+
 	if cmdLower == "clear" || cmdLower == "cls" {
 		fmt.Fprintf(c.term, "\033[2J\033[H")
 		return true
 	}
 
+	if strings.HasPrefix(cmdLower, "\\") {
+		return c.handleBackslashCommand(cmd)
+	}
+
+	if strings.HasPrefix(cmdLower, "source ") {
+		c.includeFile(strings.TrimSpace(cmd[len("source "):]))
+		return true
+	}
+
 	// ClickHouse specific commands
 	if strings.HasPrefix(cmdLower, "use ") {
-		parts := strings.Fields(cmd)
-		if len(parts) >= 2 {
-			c.useDatabase(parts[1])
+		if name := strings.TrimSpace(strings.TrimSpace(cmd)[len("use "):]); name != "" {
+			c.useDatabase(unquoteIdentifier(name))
 		}
 		return true
 	}
@@ -265,205 +571,666 @@ func (c *CLI) executeSQL(sqlStr string) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	if c.expandEnv {
+		sqlStr = c.expandEnvVars(sqlStr)
+	}
+
+	sqlStr, ok := c.checkFormatClause(sqlStr)
+	if !ok {
+		return
+	}
+
+	// 脚本中的语句不自动追加 LIMIT
+	c.limitAdded = false
+	if c.autoLimit > 0 && !c.inScript {
+		sqlStr = c.applyAutoLimit(sqlStr)
+	}
+
+	ctx, cancel := c.queryContext()
 	defer cancel()
 
-	if isQuery(sqlStr) {
+	queryID := uuid.NewString()
+	ctx = clickhouse.Context(ctx, clickhouse.WithQueryID(queryID))
+	// 驱动在有显式查询参数时不再绑定 ? 等参数，因此 \param 只用于交互语句，ExecParams 传入参数时不附加
+	if len(c.queryParams) > 0 && len(c.stmtArgs) == 0 {
+		ctx = clickhouse.Context(ctx, clickhouse.WithParameters(c.queryParams))
+	}
+	c.prevQueryID, c.lastQueryID = c.lastQueryID, queryID
+
+	var peakMemory int64
+	ctx = c.withMemoryTracking(ctx, &peakMemory)
+	ctx = c.withReadStats(ctx)
+	defer func() { c.stats = nil }()
+
+	c.lastErr = nil
+	c.disconnected = false
+	defer func() {
+		c.logStatement(sqlStr, startTime, c.lastErr)
+		if c.lastErr == nil {
+			c.trackTransaction(sqlStr)
+		}
+		if c.disconnected {
+			c.pendingSQL = sqlStr
+			fmt.Fprintf(c.term, "Statement kept. Run \\reconnect to reconnect and retry it.\n\n")
+		}
+	}()
+
+	if assignments, ok := parseSetStatement(sqlStr); ok {
+		c.executeSet(ctx, sqlStr, assignments, startTime)
+	} else if isQuery(sqlStr) && (c.render == renderServer || len(c.stmtArgs) == 0 && serverFormatted(sqlStr)) {
+		c.executeServerRendered(ctx, sqlStr, startTime)
+	} else if isQuery(sqlStr) {
 		c.executeQuery(ctx, sqlStr, startTime)
 	} else {
 		c.executeCommand(ctx, sqlStr, startTime)
 	}
+	c.checkMemWarn(peakMemory)
+}
+
+// queryContext 创建单条语句使用的上下文，queryTimeout 为 0 时不设超时
+func (c *CLI) queryContext() (context.Context, context.CancelFunc) {
+	parent := context.Background()
+	if c.stmtCtx != nil {
+		parent = c.stmtCtx
+	}
+	var ctx context.Context
+	var cancel context.CancelFunc
+	if c.queryTimeout > 0 {
+		ctx, cancel = context.WithTimeout(parent, c.queryTimeout)
+	} else {
+		ctx, cancel = context.WithCancel(parent)
+	}
+	if len(c.settings) > 0 {
+		ctx = clickhouse.Context(ctx, clickhouse.WithSettings(c.sessionSettings()))
+	}
+	return ctx, cancel
 }
 
 // executeQuery 执行查询语句
 func (c *CLI) executeQuery(ctx context.Context, sqlStr string, startTime time.Time) {
-	rows, err := c.db.QueryContext(ctx, sqlStr)
+	c.lastRowCount = 0
+	sqlStr, outFile, err := splitOutfile(sqlStr)
+	if err != nil {
+		c.printError(err)
+		return
+	}
+	sqlStr, format := splitOutputFormat(sqlStr)
+	if outFile != nil {
+		if format, err = outFile.format(sqlStr, format); err != nil {
+			c.printError(err)
+			return
+		}
+	}
+
+	// 分页器提前退出时需要能取消查询；FORMAT 输出不经过分页器
+	paged := format == "" && c.usePager(sqlStr)
+	cancel := context.CancelFunc(func() {})
+	if paged {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+	defer cancel()
+
+	var rows *sql.Rows
+	err = c.retryBusy(ctx, c.retryDisconnected(func() (err error) {
+		rows, err = c.db.QueryContext(ctx, sqlStr, c.stmtArgs...)
+		return err
+	}))
 	if err != nil {
 		c.printError(err)
 		return
 	}
 	defer rows.Close()
 
-	cols, _ := rows.Columns()
-	colTypes, _ := rows.ColumnTypes()
+	if outFile != nil {
+		c.writeOutfile(rows, outFile, format)
+		return
+	}
+	if format != "" {
+		count, err := c.writeFormatted(c.term, rows, format)
+		c.lastRowCount = count
+		if err != nil {
+			c.printError(err)
+		}
+		return
+	}
+
+	if paged {
+		c.pageRows(rows, cancel, sqlStr, startTime)
+		return
+	}
+	if c.useStream(sqlStr) {
+		c.streamTable(rows, sqlStr, startTime)
+		return
+	}
 
-	if c.verticalMode {
-		c.displayVertical(rows, cols, startTime)
-	} else {
-		c.displayTable(rows, cols, colTypes, startTime)
+	rs, err := c.collectRows(rows)
+	if err != nil {
+		c.printError(err)
+		return
+	}
+
+	scanned := len(rs.rows)
+	filter := c.grep
+	if filter != nil {
+		c.grep = nil
+		rs = c.filterRows(rs, filter)
+	}
+
+	if c.jsonOutput() {
+		c.lastRowCount = len(rs.rows)
+		c.lastResult = rs
+		c.writeResultEnvelope(rs, startTime)
+		return
+	}
+
+	// 没有流式分页时，先渲染到缓冲区再整体交给分页器
+	out := io.Writer(c.term)
+	var buffered *bytes.Buffer
+	if c.pagerReady() {
+		buffered = &bytes.Buffer{}
+		out = buffered
+	}
+
+	switch {
+	case c.verticalMode:
+		c.displayVertical(out, rs)
+	case c.transposeMode:
+		c.displayTranspose(out, rs)
+	case !c.tableFormat():
+		err = c.formatResult(out, rs)
+	case isExplainResult(rs):
+		c.displayExplain(out, rs)
+	case isExistsResult(sqlStr, rs):
+		c.displayExists(out, sqlStr, rs)
+	case isDescribeResult(sqlStr, rs):
+		c.displayDescribe(out, rs)
+	default:
+		err = c.formatResult(out, rs)
+	}
+	if err != nil {
+		c.printError(err)
+		return
+	}
+	if buffered != nil {
+		c.pageOutput(buffered.Bytes())
+	}
+
+	c.lastRowCount = len(rs.rows)
+	if rs.truncated {
+		fmt.Fprintf(c.term, "Showing first %d of %d+ rows (\\maxrows to change the limit).\n", scanned, scanned+1)
+	}
+	c.printFooter(c.term, len(rs.rows), startTime)
+	if filter != nil {
+		fmt.Fprintf(c.term, "Filter /%s/ kept %d of %d scanned rows.\n\n", filter.re, len(rs.rows), scanned)
+	}
+	c.trackPage(sqlStr)
+	if c.normalized {
+		c.showNormalized(c.lastQueryID)
+	}
+
+	c.lastResult = rs
+	if c.diffBase != nil {
+		base := c.diffBase
+		c.diffBase = nil
+		c.printResultDiff(base, rs)
+	}
+}
+
+// runQuery 执行内部查询并读取结果，供元命令使用
+func (c *CLI) runQuery(query string, args ...interface{}) (*resultSet, error) {
+	ctx, cancel := c.queryContext()
+	defer cancel()
+
+	rows, err := c.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return c.collectRows(rows)
+}
+
+// resultSet 已读取的查询结果（最多 maxRows 行）
+type resultSet struct {
+	cols      []string
+	types     []string
+	rows      [][]interface{}
+	truncated bool // 结果超过 maxRows 行，只读取了前 maxRows 行
+}
+
+// typeOf 返回第 i 列的 ClickHouse 类型，未知时返回空字符串
+func (rs *resultSet) typeOf(i int) string {
+	if i < len(rs.types) {
+		return rs.types[i]
+	}
+	return ""
+}
+
+// collectRows 读取查询结果
+func (c *CLI) collectRows(rows *sql.Rows) (*resultSet, error) {
+	cols, types, err := resultColumns(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	rs := &resultSet{cols: cols, types: types}
+
+	for rows.Next() {
+		vals, err := scanRow(rows, len(cols))
+		if err != nil {
+			return nil, err
+		}
+		rs.rows = append(rs.rows, vals)
+
+		if c.maxRows > 0 && len(rs.rows) >= c.maxRows {
+			rs.truncated = rows.Next()
+			break
+		}
+	}
+
+	return rs, rows.Err()
+}
+
+// resultColumns 返回结果的列名和 ClickHouse 类型，类型未知时为空字符串
+func resultColumns(rows *sql.Rows) ([]string, []string, error) {
+	cols, err := rows.Columns()
+	if err != nil {
+		return nil, nil, err
+	}
+	types := make([]string, len(cols))
+	if colTypes, err := rows.ColumnTypes(); err == nil {
+		for i, ct := range colTypes {
+			types[i] = ct.DatabaseTypeName()
+		}
+	}
+	return cols, types, nil
+}
+
+// scanRow 读取当前行的所有列
+func scanRow(rows *sql.Rows, n int) ([]interface{}, error) {
+	vals := make([]interface{}, n)
+	valPtrs := make([]interface{}, n)
+	for i := range vals {
+		valPtrs[i] = &vals[i]
+	}
+	if err := rows.Scan(valPtrs...); err != nil {
+		return nil, err
+	}
+	return vals, nil
+}
+
+// formatValue 将单元格值格式化为字符串，Array、Map、Tuple 按 ClickHouse 字面量语法输出
+func formatValue(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case []byte:
+		return string(val)
+	case time.Time:
+		return val.Format("2006-01-02 15:04:05")
+	default:
+		if s, ok := formatComposite(v, ""); ok {
+			return s
+		}
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// elapsedText 返回客户端耗时，服务端上报了查询耗时时一并显示，如 "Elapsed: 0.043 sec (server: 0.031 sec)."，
+// 两者之差即网络传输和结果渲染的时间
+func (c *CLI) elapsedText(elapsed float64) string {
+	if c.stats == nil || c.stats.elapsed <= 0 {
+		return fmt.Sprintf("Elapsed: %.3f sec.", elapsed)
+	}
+	return fmt.Sprintf("Elapsed: %.3f sec (server: %.3f sec).", elapsed, c.stats.elapsed.Seconds())
+}
+
+// printFooter 打印结果行数和耗时，开启计时时附带服务端读取的行数和字节数
+func (c *CLI) printFooter(w io.Writer, rowCount int, startTime time.Time) {
+	elapsed := time.Since(startTime).Seconds()
+
+	fmt.Fprintf(w, "%d rows in set.", rowCount)
+	if c.timingEnabled {
+		fmt.Fprintf(w, " %s", c.elapsedText(elapsed))
+		if c.stats != nil && c.stats.rows > 0 {
+			fmt.Fprintf(w, " %s", c.stats.processed(elapsed))
+		}
+	}
+	fmt.Fprintf(w, "\n")
+	c.printQueryID(w)
+	fmt.Fprintf(w, "\n")
+}
+
+// printQueryID 开启计时时输出本条语句的 query_id，可用于 \peek、\kill 或查询 system.query_log
+func (c *CLI) printQueryID(w io.Writer) {
+	if c.timingEnabled && c.lastQueryID != "" {
+		fmt.Fprintf(w, "Query id: %s\n", c.lastQueryID)
 	}
 }
 
 // displayTable 以表格形式显示结果
-func (c *CLI) displayTable(rows *sql.Rows, cols []string, colTypes []*sql.ColumnType, startTime time.Time) {
-	colWidths := make([]int, len(cols))
-	for i, col := range cols {
-		colWidths[i] = len(col)
+func (c *CLI) displayTable(w io.Writer, rs *resultSet) {
+	cells, colWidths := c.tableCells(rs)
+	c.renderTable(w, rs.cols, rs.types, cells, colWidths)
+}
+
+// tableCells 格式化所有单元格并计算列宽；超过 \widthlimit 的值被截断，\wrap 开启时保留完整值由输出时折行
+func (c *CLI) tableCells(rs *resultSet) ([][]string, []int) {
+	limit := c.widthLimit
+	colWidths := make([]int, len(rs.cols))
+	for i, col := range rs.cols {
+		colWidths[i] = utf8.RuneCountInString(col)
+		if c.showTypes {
+			colWidths[i] = max(colWidths[i], utf8.RuneCountInString(rs.typeOf(i)))
+		}
 		if colWidths[i] < 4 {
 			colWidths[i] = 4
 		}
-		if colWidths[i] > 50 {
-			colWidths[i] = 50
+		if limit > 0 && colWidths[i] > limit {
+			colWidths[i] = limit
 		}
 	}
 
-	var allRows [][]string
-	for rows.Next() {
-		vals := make([]interface{}, len(cols))
-		valPtrs := make([]interface{}, len(cols))
-		for i := range vals {
-			valPtrs[i] = &vals[i]
-		}
-		rows.Scan(valPtrs...)
+	collapseIdx := c.collapseIndexes(rs.cols)
+	var prevRow []string
 
+	allRows := make([][]string, 0, len(rs.rows))
+	for _, vals := range rs.rows {
 		rowStrs := make([]string, len(vals))
 		for i, v := range vals {
-			if v == nil {
+			rowStrs[i] = c.formatCell(v, rs.typeOf(i))
+		}
+		repeated := repeatedGroupColumns(collapseIdx, prevRow, rowStrs)
+		prevRow = append([]string(nil), rowStrs...)
+
+		for i := range rowStrs {
+			if repeated[i] {
 				rowStrs[i] = ""
-			} else {
-				switch val := v.(type) {
-				case []byte:
-					rowStrs[i] = string(val)
-				case time.Time:
-					rowStrs[i] = val.Format("2006-01-02 15:04:05")
-				default:
-					rowStrs[i] = fmt.Sprintf("%v", v)
-				}
 			}
-
-			if len(rowStrs[i]) > colWidths[i] {
-				if len(rowStrs[i]) > 50 {
-					colWidths[i] = 50
-					rowStrs[i] = rowStrs[i][:47] + "..."
-				} else {
-					colWidths[i] = len(rowStrs[i])
-				}
+			rowStrs[i] = c.limitCell(rowStrs[i])
+			width := cellWidth(rowStrs[i], c.wrapCells)
+			if limit > 0 && width > limit {
+				width = limit
 			}
+			colWidths[i] = max(colWidths[i], width)
 		}
 		allRows = append(allRows, rowStrs)
+	}
 
-		if len(allRows) >= c.maxRows {
-			break
-		}
+	return allRows, colWidths
+}
+
+// renderTable 按给定列宽和 \format 样式输出表格，数值列右对齐，列对齐方式可用 \align 覆盖
+func (c *CLI) renderTable(w io.Writer, cols, types []string, allRows [][]string, colWidths []int) {
+	st := c.tableStyle()
+	right := c.columnAlign(cols, types)
+	st.writeHeader(w, cols, c.headerTypes(types), colWidths, right)
+	for _, row := range allRows {
+		st.writeRow(w, row, colWidths, right)
 	}
+	st.writeRule(w, st.bottom, colWidths)
+	fmt.Fprintf(w, "\n")
+}
 
+// writeHeader 输出表头和分隔线，types 不为 nil 时在列名下方输出列类型；right 为 nil 时全部左对齐
+func (st *tableStyle) writeHeader(w io.Writer, cols, types []string, colWidths []int, right []bool) {
 	// ClickHouse style table output
-	for i, col := range cols {
-		if i > 0 {
-			fmt.Fprintf(c.term, " │ ")
+	st.writeRule(w, st.top, colWidths)
+	st.writeCells(w, cols, colWidths, right, st.headerColor(ansiHeader))
+	if types != nil {
+		row := make([]string, len(cols))
+		for i := range cols {
+			if i < len(types) {
+				row[i] = st.fit(types[i], colWidths[i])
+			}
 		}
-		fmt.Fprintf(c.term, "%-*s", colWidths[i], col)
+		st.writeCells(w, row, colWidths, right, st.headerColor(ansiDim))
 	}
-	fmt.Fprintf(c.term, "\n")
+	st.writeRule(w, st.mid, colWidths)
+}
 
-	for i := range cols {
-		if i > 0 {
-			fmt.Fprintf(c.term, "─┼─")
-		}
-		fmt.Fprintf(c.term, "%s", strings.Repeat("─", colWidths[i]))
-	}
-	fmt.Fprintf(c.term, "\n")
+// writeRow 输出一行已格式化的单元格，right 为 nil 时全部左对齐
+func (st *tableStyle) writeRow(w io.Writer, row []string, colWidths []int, right []bool) {
+	st.writeCells(w, row, colWidths, right, st.cellColor)
+}
 
-	for _, row := range allRows {
-		for i, val := range row {
+// writeCells 按列宽填充并输出一行，color 返回每个单元格的颜色，空字符串表示不加颜色；
+// 折行模式下超出列宽或含换行的单元格拆成多个输出行，其他列在后续行中留空
+func (st *tableStyle) writeCells(w io.Writer, row []string, colWidths []int, right []bool, color func(val string, right bool) string) {
+	lines := [][]string{row}
+	if st.wrap {
+		lines = wrapRow(row, colWidths)
+	}
+	for _, line := range lines {
+		fmt.Fprintf(w, "%s", st.left)
+		for i, val := range line {
 			if i > 0 {
-				fmt.Fprintf(c.term, " │ ")
+				fmt.Fprintf(w, "%s", st.sep)
+			}
+			alignRight := i < len(right) && right[i]
+			cell := padCell(val, colWidths[i], alignRight)
+			if val != "" {
+				cell = colored(cell, color(row[i], alignRight))
 			}
-			fmt.Fprintf(c.term, "%-*s", colWidths[i], val)
+			fmt.Fprintf(w, "%s", cell)
 		}
-		fmt.Fprintf(c.term, "\n")
+		fmt.Fprintf(w, "%s\n", st.right)
 	}
+}
 
-	rowCount := len(allRows)
-	elapsed := time.Since(startTime).Seconds()
+// headerColor 返回表头单元格使用的颜色函数，未开启颜色时不加颜色
+func (st *tableStyle) headerColor(code string) func(string, bool) string {
+	return func(string, bool) string {
+		if !st.color {
+			return ""
+		}
+		return code
+	}
+}
 
-	fmt.Fprintf(c.term, "\n%d rows in set.", rowCount)
-	if c.timingEnabled {
-		fmt.Fprintf(c.term, " Elapsed: %.3f sec.", elapsed)
+// writeRule 按列宽输出一条横线，line 为 nil 时不输出；开启颜色时与列名同色
+func (st *tableStyle) writeRule(w io.Writer, line *ruleLine, colWidths []int) {
+	if line == nil {
+		return
+	}
+	var b strings.Builder
+	b.WriteString(line.left)
+	for i, width := range colWidths {
+		if i > 0 {
+			b.WriteString(line.cross)
+		}
+		b.WriteString(strings.Repeat(line.fill, width))
+	}
+	b.WriteString(line.right)
+	rule := b.String()
+	if st.color {
+		rule = colored(rule, ansiHeader)
 	}
-	fmt.Fprintf(c.term, "\n\n")
+	fmt.Fprintf(w, "%s\n", rule)
 }
 
 // displayVertical 以垂直形式显示结果
-func (c *CLI) displayVertical(rows *sql.Rows, cols []string, startTime time.Time) {
-	rowNum := 0
-	for rows.Next() {
-		rowNum++
-		vals := make([]interface{}, len(cols))
-		valPtrs := make([]interface{}, len(cols))
-		for i := range vals {
-			valPtrs[i] = &vals[i]
+func (c *CLI) displayVertical(w io.Writer, rs *resultSet) {
+	maxColLen := 0
+	for _, col := range rs.cols {
+		if len(col) > maxColLen {
+			maxColLen = len(col)
 		}
-		rows.Scan(valPtrs...)
+	}
 
-		fmt.Fprintf(c.term, "Row %d:\n", rowNum)
-		fmt.Fprintf(c.term, "%s\n", strings.Repeat("─", 50))
+	collapseIdx := c.collapseIndexes(rs.cols)
+	var prevRow []string
 
-		maxColLen := 0
-		for _, col := range cols {
-			if len(col) > maxColLen {
-				maxColLen = len(col)
-			}
+	// Nested 列的各成员列合并为一个子表，在首个成员列的位置输出
+	nested := nestedGroups(rs)
+	members := make(map[int]bool)
+	for _, g := range nested {
+		for _, i := range g.fields {
+			members[i] = true
 		}
+	}
 
-		for i, col := range cols {
-			var valStr string
-			if vals[i] == nil {
-				valStr = ""
-			} else {
-				switch val := vals[i].(type) {
-				case []byte:
-					valStr = string(val)
-				case time.Time:
-					valStr = val.Format("2006-01-02 15:04:05")
-				default:
-					valStr = fmt.Sprintf("%v", val)
-				}
+	for rowNum, vals := range rs.rows {
+		fmt.Fprintf(w, "Row %d:\n", rowNum+1)
+		fmt.Fprintf(w, "%s\n", strings.Repeat("─", 50))
+
+		var repeated map[int]bool
+		if collapseIdx != nil {
+			cur := make([]string, len(vals))
+			for i, v := range vals {
+				cur[i] = c.formatCell(v, rs.typeOf(i))
 			}
-			fmt.Fprintf(c.term, "%-*s: %s\n", maxColLen, col, valStr)
+			repeated = repeatedGroupColumns(collapseIdx, prevRow, cur)
+			prevRow = cur
 		}
-		fmt.Fprintf(c.term, "\n")
 
-		if rowNum >= c.maxRows {
-			break
+		for i, col := range rs.cols {
+			if repeated[i] {
+				continue
+			}
+			if g := nested[i]; g != nil {
+				fmt.Fprintf(w, "%-*s:\n", maxColLen, g.name)
+				c.writeNested(w, rs, vals, g)
+				continue
+			}
+			if members[i] {
+				continue
+			}
+			if isNestedValue(vals[i]) {
+				fmt.Fprintf(w, "%-*s:\n", maxColLen, col)
+				c.writeTree(w, vals[i], rs.typeOf(i), 1)
+				continue
+			}
+			fmt.Fprintf(w, "%-*s: %s\n", maxColLen, col, c.limitCell(c.formatCell(vals[i], rs.typeOf(i))))
 		}
+		fmt.Fprintf(w, "\n")
 	}
+}
 
-	elapsed := time.Since(startTime).Seconds()
-	fmt.Fprintf(c.term, "%d rows in set.", rowNum)
-	if c.timingEnabled {
-		fmt.Fprintf(c.term, " Elapsed: %.3f sec.", elapsed)
+// displayTranspose 以带边框的 name/value 两列表格显示每一行
+func (c *CLI) displayTranspose(w io.Writer, rs *resultSet) {
+	nameWidth := utf8.RuneCountInString("name")
+	for _, col := range rs.cols {
+		nameWidth = max(nameWidth, utf8.RuneCountInString(col))
+	}
+
+	for rowNum, vals := range rs.rows {
+		valStrs := make([]string, len(vals))
+		valueWidth := utf8.RuneCountInString("value")
+		for i, v := range vals {
+			valStrs[i] = c.formatCell(v, rs.typeOf(i))
+			valueWidth = max(valueWidth, utf8.RuneCountInString(valStrs[i]))
+		}
+
+		if len(rs.rows) > 1 {
+			fmt.Fprintf(w, "Row %d:\n", rowNum+1)
+		}
+		fmt.Fprintf(w, "┌─%s─┬─%s─┐\n", strings.Repeat("─", nameWidth), strings.Repeat("─", valueWidth))
+		fmt.Fprintf(w, "│ %-*s │ %-*s │\n", nameWidth, "name", valueWidth, "value")
+		fmt.Fprintf(w, "├─%s─┼─%s─┤\n", strings.Repeat("─", nameWidth), strings.Repeat("─", valueWidth))
+		for i, col := range rs.cols {
+			fmt.Fprintf(w, "│ %-*s │ %-*s │\n", nameWidth, col, valueWidth, valStrs[i])
+		}
+		fmt.Fprintf(w, "└─%s─┴─%s─┘\n\n", strings.Repeat("─", nameWidth), strings.Repeat("─", valueWidth))
 	}
-	fmt.Fprintf(c.term, "\n\n")
 }
 
 // executeCommand 执行非查询语句
 func (c *CLI) executeCommand(ctx context.Context, sqlStr string, startTime time.Time) {
-	result, err := c.db.ExecContext(ctx, sqlStr)
+	ctx, progress := c.withWriteProgress(ctx, sqlStr)
+
+	var result sql.Result
+	err := c.retryBusy(ctx, c.retryDisconnected(func() (err error) {
+		result, err = c.db.ExecContext(ctx, sqlStr, c.stmtArgs...)
+		return err
+	}))
+	if progress != nil {
+		progress.clear()
+	}
 	if err != nil {
 		c.printError(err)
 		return
 	}
 
+	if isSchemaChange(sqlStr) {
+		c.schema.reset()
+	}
+
 	affected, _ := result.RowsAffected()
+	if c.jsonOutput() {
+		c.writeAffectedEnvelope(affected, startTime)
+		return
+	}
 	elapsed := time.Since(startTime).Seconds()
 
-	fmt.Fprintf(c.term, "Ok. %d rows affected.", affected)
+	if progress != nil && progress.rows > 0 {
+		fmt.Fprintf(c.term, "Ok. %d rows written (%.0f rows/sec).", progress.rows, progress.rate())
+	} else {
+		fmt.Fprintf(c.term, "Ok. %d rows affected.", affected)
+	}
 	if c.timingEnabled {
-		fmt.Fprintf(c.term, " Elapsed: %.3f sec.", elapsed)
+		fmt.Fprintf(c.term, " %s", c.elapsedText(elapsed))
+		if c.stats != nil && c.stats.rows > 0 {
+			fmt.Fprintf(c.term, " %s", c.stats.processed(elapsed))
+		}
 	}
-	fmt.Fprintf(c.term, "\n\n")
+	fmt.Fprintf(c.term, "\n")
+	c.printQueryID(c.term)
+	fmt.Fprintf(c.term, "\n")
 }
 
-// useDatabase 切换数据库
+// useDatabase 确认数据库存在后切换：以新的默认库重建连接池，之后池中每个连接都在该库下执行未限定库名的表，
+// 而不是只修改本地状态；库不存在或重建失败时保留原来的库
 func (c *CLI) useDatabase(dbName string) {
+	ctx, cancel := c.queryContext()
+	defer cancel()
+	var exists uint8
+	if err := c.db.QueryRowContext(ctx, "SELECT count() > 0 FROM system.databases WHERE name = ?", dbName).Scan(&exists); err != nil {
+		c.printError(err)
+		return
+	}
+	if exists == 0 {
+		c.printError(fmt.Errorf("database %s does not exist", dbName))
+		return
+	}
+
+	oldDatabase := c.database
 	c.database = dbName
+	if err := c.reopen(); err != nil {
+		c.database = oldDatabase
+		c.printError(err)
+		return
+	}
 	fmt.Fprintf(c.term, "Ok.\n")
 }
 
-// printError 打印错误信息
+// printError 打印错误信息：服务端异常显示真实错误码和异常名，其他错误（网络、超时等）不带错误码
 func (c *CLI) printError(err error) {
-	fmt.Fprintf(c.term, "Code: 0. DB::Exception: %s\n\n", err.Error())
+	c.lastErr = err
+	if c.jsonOutput() {
+		c.writeErrorEnvelope(err)
+		return
+	}
+	if c.cancelled() {
+		fmt.Fprintf(c.term, "Query was cancelled.\n\n")
+		return
+	}
+	if errors.Is(err, context.DeadlineExceeded) && c.queryTimeout > 0 {
+		fmt.Fprintf(c.term, "Error: client-side timeout of %s exceeded. Raise it with \\timeout <seconds> or \\timeout off.\n\n",
+			c.queryTimeout)
+		return
+	}
+	var connErr *connectError
+	exception, ok := serverException(err)
+	if errors.As(err, &connErr) || !ok {
+		fmt.Fprintf(c.term, "Error: %s\n\n", err.Error())
+		return
+	}
+	name := exception.Name
+	if name == "" {
+		name = "DB::Exception"
+	}
+	fmt.Fprintf(c.term, "Code: %d. %s: %s\n\n", exception.Code, name, exception.Message)
 }
 
 // showHelp 显示帮助信息
@@ -473,23 +1240,72 @@ ClickHouse Commands
 ===================
 
 General:
-  help, \\h                Show this help
+  help, \\h               Show this help
+  \\h <command>           Show usage and an example for one command
   exit, quit, \\q         Exit
   clear, cls              Clear screen
   timing, \\timing        Toggle timing
   vertical, \\G           Toggle vertical output
+  \\transpose             Toggle name/value card output
+  \\format [name]         Set output format: table styles, Vertical, CSV, TSV, JSON, JSONEachRow, Markdown, HTML
+  \\render server|client  Let the server (HTTP) or the CLI format results
+  \\pager [on|off|auto|cmd]
+                          Show results through a pager ($PAGER or less -SR); auto: only taller than the screen
+  \\output [text|json]    One JSON envelope per result/error for scripting
+  \\types [on|off]        Show column types under the header
+  \\align col:left|right,...|reset
+                          Override the alignment of table columns
+  \\collapse [on|off|cols] Blank repeated values in group columns
+  \\datetime <mode>       Show DateTime as iso, epoch or epoch_ms
+  \\nullvalue [str]       Text shown for NULL (default ᴺᵁᴸᴸ)
+  \\pretty numbers [on|off] [sep]
+                          Group digits of numeric columns, e.g. 1,234,567
+  \\color [on|off|auto]   Color headers, NULLs and numbers (auto: TTY without NO_COLOR)
+  \\widthlimit [n]        Cap table column width (default 50, 0 = no limit)
+  \\wrap [on|off]         Wrap long values within the column instead of cutting them
+  \\next, \\prev          Page through the last SELECT ... LIMIT n
+  \\limit <n>|off         Append LIMIT n to interactive SELECTs without one
+  \\timeout [sec|off]     Client-side timeout per statement (default 60s)
+  \\maxrows [n]           Show at most n rows of a result (0 = unlimited)
+  \\watch <sec> <query>  Re-run a query every sec seconds until Ctrl-C
+  \\i [--continue] <path>
+                          Run the statements in a .sql file (also: source <path>)
+  \\assert [--save] <path> Compare the last result with a golden file
+  \\checksum <query>      Row count and order-independent hash of a result
+  \\diff-result           Diff the next query's rows against the last result
+  \\grep [-v] <pattern>   Filter the next query's rows by regex (-v hides)
+  \\memwarn [size|off]    Warn when a query's peak memory exceeds size (e.g. 40G)
+  \\expandenv [on|off]    Substitute ${VAR} from the environment in SQL
 
 Database:
   USE <database>          Change database
   SHOW DATABASES          List databases
   SHOW TABLES             List tables
   SHOW CREATE TABLE t     Show table DDL
+  \\copy-ddl <table>      Show portable DDL (IF NOT EXISTS, no UUID)
+  \\cluster [name|off]    Set cluster used for ON CLUSTER
+  \\reconnect             Reconnect and retry a statement that failed on a dead connection
+  \\connect host[:port] [user] [db]
+                          Connect to another server (prompts for password)
 
 Query Commands:
   SELECT ...              Query data
   SELECT ... FORMAT JSON  Query with JSON format
   SELECT ... FORMAT CSV   Query with CSV format
+  SELECT ... INTO OUTFILE '<path>' [APPEND|TRUNCATE] [FORMAT fmt]
+                          Write the result to a local file
+  \\l                     SHOW DATABASES
+  \\dt [database]         SHOW TABLES (current database by default)
+  \\d <table>             DESCRIBE TABLE
+  \\d+ <table>            SHOW CREATE TABLE
   INSERT INTO ...         Insert data
+  \\sql-from-clipboard    Load the terminal clipboard (OSC 52) into the prompt
+  \\values <table>        Guided row-by-row INSERT with per-column prompts
+  \\paste <table>         Insert pasted TSV/CSV rows (end with empty line)
+  \\load <file> INTO <table> FORMAT CSV|TSV[WithNames]
+                          Bulk-load a local CSV/TSV file through batch INSERTs
+  \\import-errors skip|abort|report [path]
+                          How \\paste handles rows that fail to insert
   
 DDL Commands:
   CREATE TABLE ...        Create table
@@ -497,6 +1313,19 @@ DDL Commands:
   DROP TABLE ...          Drop table
   ALTER TABLE ...         Alter table
   OPTIMIZE TABLE ...      Optimize table
+  \\tail [-f] <table> [ts_column] [n]
+                          Latest rows by the table's DateTime column (-f follows)
+  \\parts <table>         Active MergeTree parts with sizes and a fragmentation check
+  \\normalized [on|off]   Show the server-side form of each query
+  \\size-estimate <query> EXPLAIN ESTIMATE, then confirm before running
+  \\ast <query>           EXPLAIN AST rendered as a tree
+  \\replication [table]   Replica queue, delay and health verdict
+  \\check <table>         Run CHECK TABLE and summarize per part
+
+Dictionaries:
+  \\dicts                 List dictionaries with status and memory
+  \\dict <name>           Show source, layout, lifetime and structure
+  \\dict-reload <name>    SYSTEM RELOAD DICTIONARY
   
 System Tables:
   SELECT * FROM system.databases
@@ -504,6 +1333,27 @@ System Tables:
   SELECT * FROM system.columns WHERE database='db' AND table='t'
   SELECT * FROM system.processes      -- Show running queries
   SELECT * FROM system.query_log      -- Query log
+  \\join-help             Show join-related settings with explanations
+  \\join-algo <name>      Set join_algorithm for this session
+  \\settings              List session settings sent with every query (from SET)
+  \\param [name value|reset]
+                          Value for {name:Type} placeholders, sent as a query parameter
+  \\unset <name>|all      Remove session settings
+  \\settings-diff         Show settings changed from defaults (server and SET)
+  \\whoami                Current user, roles, database and admin status
+  \\now                   Server and client time with clock skew
+  \\peek <query_id>       Show text, progress and memory of a running query
+  \\kill [query_id]       KILL QUERY by id (default: the last statement of this session)
+  \\querylog <path|off>   Append every statement with time, status to a file
+  \\flush-logs            SYSTEM FLUSH LOGS
+  \\reload-config         SYSTEM RELOAD CONFIG
+  \\drop-cache <name|all> SYSTEM DROP ... CACHE (mark, uncompressed, dns, ...)
+  \\sync-replica <table>  SYSTEM SYNC REPLICA
+  \\kill-mine             Kill all running queries of the current user
+  \\top [n] [metric] [window]
+                          Heaviest finished queries from query_log
+  \\compare-events [id1 id2]
+                          Diff ProfileEvents of two queries (default: last two)
   
 ClickHouse Specific:
   DESCRIBE TABLE t        Describe table structure
@@ -518,27 +1368,20 @@ For more: https://clickhouse.com/docs/
 
 // Close 关闭数据库连接
 func (c *CLI) Close() error {
+	c.closeQueryLog()
 	if c.db != nil {
 		return c.db.Close()
 	}
 	return nil
 }
 
-// isQuery 判断是否是查询语句
+// isQuery 判断是否是返回结果集的查询语句，决定交给 executeQuery 还是 executeCommand
 func isQuery(sqlStr string) bool {
-	upper := strings.ToUpper(strings.TrimSpace(sqlStr))
-
-	queryPrefixes := []string{
-		"SELECT", "SHOW", "DESC", "DESCRIBE",
-		"EXISTS", "EXPLAIN", "WITH",
-	}
-
-	for _, prefix := range queryPrefixes {
-		if strings.HasPrefix(upper, prefix) {
-			return true
-		}
+	// 按实际的动词判断，跳过开头的注释和 WITH 子句；INSERT ... SELECT、CREATE ... AS SELECT 属于命令
+	switch statementVerb(sqlStr) {
+	case "SELECT", "SHOW", "DESC", "DESCRIBE", "EXISTS", "EXPLAIN", "WITH":
+		return true
 	}
-
 	return false
 }
 
@@ -547,4 +1390,3 @@ func parseInt(s string) int {
 	i, _ := strconv.Atoi(s)
 	return i
 }
-
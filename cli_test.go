@@ -0,0 +1,47 @@
+package clickhouse
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+)
+
+func TestPrintErrorServerException(t *testing.T) {
+	exception := &clickhouse.Exception{
+		Code:       60,
+		Name:       "DB::Exception",
+		Message:    "Table default.x doesn't exist",
+		StackTrace: "0. DB::Exception::Exception() @ 0x1\n1. DB::Context::getTable() @ 0x2",
+	}
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"exception", exception, "Code: 60. DB::Exception: Table default.x doesn't exist\n\n"},
+		{"wrapped", fmt.Errorf("query failed: %w", exception), "Code: 60. DB::Exception: Table default.x doesn't exist\n\n"},
+		{"unnamed", &clickhouse.Exception{Code: 62, Message: "Syntax error"}, "Code: 62. DB::Exception: Syntax error\n\n"},
+		{"http", errors.New("clickhouse [execute]:: 404 code: Code: 81. DB::Exception: Database nope does not exist. (UNKNOWN_DATABASE)"),
+			"Code: 81. DB::Exception: Database nope does not exist. (UNKNOWN_DATABASE)\n\n"},
+		{"generic", errors.New("dial tcp 127.0.0.1:9000: connect: connection refused"),
+			"Error: dial tcp 127.0.0.1:9000: connect: connection refused\n\n"},
+	}
+	for _, tt := range tests {
+		var term bytes.Buffer
+		c := NewCLI(&term, "localhost", 9000, "default", "", "default")
+		c.printError(tt.err)
+		if term.String() != tt.want {
+			t.Errorf("%s: printError printed %q, want %q", tt.name, term.String(), tt.want)
+		}
+		if strings.Contains(term.String(), "Code: 0.") || strings.Contains(term.String(), "getTable") {
+			t.Errorf("%s: printError printed a fake code or the stack trace: %q", tt.name, term.String())
+		}
+		if c.lastErr != tt.err {
+			t.Errorf("%s: lastErr = %v, want the printed error", tt.name, c.lastErr)
+		}
+	}
+}
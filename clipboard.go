@@ -0,0 +1,129 @@
+package clickhouse
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chzyer/readline"
+)
+
+// clipboardTimeout 等待终端响应 OSC 52 查询的时间
+const clipboardTimeout = 2 * time.Second
+
+// sqlFromClipboard 通过 OSC 52 读取终端剪贴板，放入下一次输入的编辑缓冲区
+func (c *CLI) sqlFromClipboard() {
+	text, err := c.readClipboard()
+	if err != nil {
+		fmt.Fprintf(c.term, "Could not read the clipboard: %v\n", err)
+		return
+	}
+
+	text = strings.TrimSpace(text)
+	if text == "" {
+		fmt.Fprintf(c.term, "Clipboard is empty.\n")
+		return
+	}
+
+	// 编辑缓冲区只有一行，多行 SQL 合并为一行
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(line)
+		if strings.Contains(lines[i], "--") {
+			fmt.Fprintf(c.term, "Warning: line %d has a -- comment, which now comments out the rest of the query.\n", i+1)
+		}
+	}
+	c.reader.SetInitial(strings.Join(lines, " "))
+	fmt.Fprintf(c.term, "Loaded %d characters from the clipboard.\n", len(text))
+}
+
+// readClipboard 发送 OSC 52 查询并读取终端回复，终端不支持时超时返回错误
+func (c *CLI) readClipboard() (string, error) {
+	// 本地终端需要进入 raw 模式，否则回复要等到回车才能读到
+	if f, ok := c.term.(*os.File); ok && readline.IsTerminal(int(f.Fd())) {
+		state, err := readline.MakeRaw(int(f.Fd()))
+		if err != nil {
+			return "", err
+		}
+		defer readline.Restore(int(f.Fd()), state)
+	}
+
+	fmt.Fprintf(c.term, "\x1b]52;c;?\x07")
+
+	var (
+		mu        sync.Mutex
+		abandoned bool
+	)
+	result := make(chan []byte, 1)
+	go func() {
+		var buf []byte
+		b := make([]byte, 256)
+		for {
+			n, err := c.term.Read(b)
+			mu.Lock()
+			if abandoned {
+				// 已超时：把读到的按键交还给 readline 后退出
+				mu.Unlock()
+				if n > 0 {
+					c.reader.WriteStdin(b[:n])
+				}
+				return
+			}
+			mu.Unlock()
+			if err != nil {
+				result <- nil
+				return
+			}
+			buf = append(buf, b[:n]...)
+			if payload, ok := parseOSC52Reply(buf); ok {
+				result <- payload
+				return
+			}
+		}
+	}()
+
+	select {
+	case payload := <-result:
+		if payload == nil {
+			return "", errors.New("no reply from terminal")
+		}
+		data, err := base64.StdEncoding.DecodeString(string(payload))
+		if err != nil {
+			return "", fmt.Errorf("invalid clipboard data: %v", err)
+		}
+		return string(data), nil
+	case <-time.After(clipboardTimeout):
+		mu.Lock()
+		abandoned = true
+		mu.Unlock()
+		return "", errors.New("terminal did not answer the OSC 52 request (unsupported or disabled)")
+	}
+}
+
+// parseOSC52Reply 从缓冲区中提取 ESC ] 52 ; c ; <base64> 的 base64 部分，
+// 结束符可以是 BEL 或 ESC \
+func parseOSC52Reply(buf []byte) ([]byte, bool) {
+	start := bytes.Index(buf, []byte("\x1b]52;"))
+	if start < 0 {
+		return nil, false
+	}
+	rest := buf[start+len("\x1b]52;"):]
+	semi := bytes.IndexByte(rest, ';')
+	if semi < 0 {
+		return nil, false
+	}
+	rest = rest[semi+1:]
+
+	if end := bytes.IndexByte(rest, '\x07'); end >= 0 {
+		return rest[:end], true
+	}
+	if end := bytes.Index(rest, []byte("\x1b\\")); end >= 0 {
+		return rest[:end], true
+	}
+	return nil, false
+}
@@ -0,0 +1,80 @@
+package clickhouse
+
+import (
+	"fmt"
+	"strings"
+)
+
+// setCollapse 处理 \collapse on|off|col1,col2：开启后连续重复的分组列值只显示第一次
+func (c *CLI) setCollapse(args string) {
+	switch strings.ToLower(args) {
+	case "":
+		if !c.collapse {
+			fmt.Fprintf(c.term, "Collapse is off.\n")
+		} else if len(c.collapseCols) == 0 {
+			fmt.Fprintf(c.term, "Collapse is on (first column).\n")
+		} else {
+			fmt.Fprintf(c.term, "Collapse is on (%s).\n", strings.Join(c.collapseCols, ", "))
+		}
+		return
+	case "on":
+		c.collapse = true
+	case "off":
+		c.collapse = false
+		c.collapseCols = nil
+		fmt.Fprintf(c.term, "Collapse disabled.\n")
+		return
+	default:
+		var cols []string
+		for _, col := range strings.Split(args, ",") {
+			if col = strings.TrimSpace(col); col != "" {
+				cols = append(cols, col)
+			}
+		}
+		c.collapse = true
+		c.collapseCols = cols
+	}
+
+	if len(c.collapseCols) == 0 {
+		fmt.Fprintf(c.term, "Collapse enabled on the first column.\n")
+	} else {
+		fmt.Fprintf(c.term, "Collapse enabled on %s.\n", strings.Join(c.collapseCols, ", "))
+	}
+}
+
+// collapseIndexes 返回需要折叠的列下标，未开启或结果中没有指定列时返回 nil
+func (c *CLI) collapseIndexes(cols []string) []int {
+	if !c.collapse || len(cols) == 0 {
+		return nil
+	}
+	if len(c.collapseCols) == 0 {
+		return []int{0}
+	}
+
+	var idx []int
+	for _, name := range c.collapseCols {
+		for i, col := range cols {
+			if strings.EqualFold(col, name) {
+				idx = append(idx, i)
+				break
+			}
+		}
+	}
+	return idx
+}
+
+// repeatedGroupColumns 返回当前行中与上一行重复、应留空的分组列
+// 按多级索引的方式处理：前一级分组变化后，后面的列不再折叠
+func repeatedGroupColumns(idx []int, prev, cur []string) map[int]bool {
+	if prev == nil {
+		return nil
+	}
+	repeated := make(map[int]bool)
+	for _, i := range idx {
+		if prev[i] != cur[i] {
+			break
+		}
+		repeated[i] = true
+	}
+	return repeated
+}
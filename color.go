@@ -0,0 +1,78 @@
+package clickhouse
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// 颜色模式
+const (
+	colorOn   = "on"
+	colorOff  = "off"
+	colorAuto = "auto"
+)
+
+// 表格输出使用的 ANSI 转义序列
+const (
+	ansiReset  = "\x1b[0m"
+	ansiHeader = "\x1b[1;36m" // 列名和表头分隔线
+	ansiDim    = "\x1b[2m"    // NULL 和列类型
+	ansiNumber = "\x1b[33m"   // 数值列
+)
+
+// setColor 处理 \color [on|off|auto]：auto 在输出不是终端或设置了 NO_COLOR 时不使用颜色
+func (c *CLI) setColor(args string) {
+	mode := strings.ToLower(args)
+	switch mode {
+	case "":
+		fmt.Fprintf(c.term, "Color: %s (%s).\n", c.color, c.colorState())
+		return
+	case colorOn, colorOff, colorAuto:
+		c.color = mode
+	default:
+		fmt.Fprintf(c.term, "Usage: \\color on|off|auto\n")
+		return
+	}
+	fmt.Fprintf(c.term, "Color set to %s (%s).\n", c.color, c.colorState())
+}
+
+// colorState 返回当前是否实际输出颜色的描述
+func (c *CLI) colorState() string {
+	if c.useColor() {
+		return "enabled"
+	}
+	return "disabled"
+}
+
+// useColor 判断表格输出是否加颜色；CSV、JSON 和 INTO OUTFILE 等输出不经过表格样式，始终不带颜色
+func (c *CLI) useColor() bool {
+	switch c.color {
+	case colorOn:
+		return true
+	case colorOff:
+		return false
+	}
+	return os.Getenv("NO_COLOR") == "" && c.isLocalTerminal()
+}
+
+// colored 用颜色包围文本，code 为空时原样返回
+func colored(s, code string) string {
+	if code == "" || s == "" {
+		return s
+	}
+	return code + s + ansiReset
+}
+
+// cellColor 返回数据单元格的颜色：NULL 变暗，右对齐的数值列与字符串列区分开
+func (st *tableStyle) cellColor(val string, right bool) string {
+	switch {
+	case !st.color || strings.TrimSpace(val) == "":
+		return ""
+	case val == st.null:
+		return ansiDim
+	case right:
+		return ansiNumber
+	}
+	return ""
+}
@@ -0,0 +1,179 @@
+package clickhouse
+
+import (
+	"fmt"
+	"strings"
+)
+
+// handleBackslashCommand 处理以反斜杠开头的元命令
+func (c *CLI) handleBackslashCommand(cmd string) bool {
+	name, args := splitCommand(cmd)
+
+	switch strings.ToLower(name) {
+	case "\\next":
+		c.nextPage()
+	case "\\prev":
+		c.prevPage()
+	case "\\limit":
+		c.setAutoLimit(args)
+	case "\\i", "\\source":
+		c.includeFile(args)
+	case "\\maxrows":
+		c.setMaxRows(args)
+	case "\\timeout":
+		c.setQueryTimeout(args)
+	case "\\transpose":
+		c.transposeMode = !c.transposeMode
+		if c.transposeMode {
+			c.verticalMode = false
+			fmt.Fprintf(c.term, "Transposed output mode enabled.\n")
+		} else {
+			fmt.Fprintf(c.term, "Transposed output mode disabled.\n")
+		}
+	case "\\copy-ddl":
+		c.copyDDL(args)
+	case "\\cluster":
+		c.setCluster(args)
+	case "\\connect", "\\c":
+		c.connectTo(args)
+	case "\\reconnect":
+		c.reconnect()
+	case "\\tail":
+		c.tailTable(args)
+	case "\\normalized":
+		c.setNormalized(args)
+	case "\\size-estimate":
+		c.sizeEstimate(args)
+	case "\\ast":
+		c.showAST(args)
+	case "\\replication":
+		c.showReplication(args)
+	case "\\parts":
+		c.showParts(args)
+	case "\\check":
+		c.checkTable(args)
+	case "\\render":
+		c.setRender(args)
+	case "\\format":
+		c.setFormat(args)
+	case "\\pager":
+		c.setPager(args)
+	case "\\output":
+		c.setOutput(args)
+	case "\\align":
+		c.setAlign(args)
+	case "\\types":
+		c.setShowTypes(args)
+	case "\\collapse":
+		c.setCollapse(args)
+	case "\\datetime":
+		c.setDatetimeMode(args)
+	case "\\widthlimit":
+		c.setWidthLimit(args)
+	case "\\wrap":
+		c.setWrap(args)
+	case "\\color":
+		c.setColor(args)
+	case "\\pretty":
+		c.setPretty(args)
+	case "\\nullvalue":
+		c.setNullValue(args)
+	case "\\memwarn":
+		c.setMemWarn(args)
+	case "\\grep":
+		c.setGrep(args)
+	case "\\assert":
+		c.assertResult(args)
+	case "\\checksum":
+		c.checksumQuery(args)
+	case "\\diff-result":
+		c.markDiffBase()
+	case "\\expandenv":
+		c.setExpandEnv(args)
+	case "\\l":
+		c.executeSQL("SHOW DATABASES")
+	case "\\dt":
+		c.listTables(args)
+	case "\\d", "\\d+":
+		c.describeShortcut(strings.ToLower(name), args)
+	case "\\dicts":
+		c.listDictionaries()
+	case "\\dict":
+		c.describeDictionary(args)
+	case "\\dict-reload":
+		c.reloadDictionary(args)
+	case "\\sql-from-clipboard":
+		c.sqlFromClipboard()
+	case "\\values":
+		c.enterValues(args)
+	case "\\paste":
+		c.pasteRows(args)
+	case "\\load":
+		c.loadFile(args)
+	case "\\watch":
+		c.watchQuery(args)
+	case "\\import-errors":
+		c.setImportErrors(args)
+	case "\\join-help":
+		c.joinHelp()
+	case "\\join-algo":
+		c.setJoinAlgorithm(args)
+	case "\\settings":
+		c.showSessionSettings()
+	case "\\param":
+		c.setParam(args)
+	case "\\unset":
+		c.unsetSettings(args)
+	case "\\settings-diff":
+		c.settingsDiff()
+	case "\\whoami":
+		c.showWhoami()
+	case "\\now":
+		c.showNow()
+	case "\\peek":
+		c.peekQuery(args)
+	case "\\querylog", "\\log-query":
+		c.setQueryLog(args)
+	case "\\flush-logs":
+		c.flushLogs()
+	case "\\reload-config":
+		c.reloadConfig()
+	case "\\drop-cache":
+		c.dropCache(args)
+	case "\\sync-replica":
+		c.syncReplica(args)
+	case "\\kill":
+		c.killQuery(args)
+	case "\\kill-mine":
+		c.killMyQueries()
+	case "\\top":
+		c.showTopQueries(args)
+	case "\\compare-events":
+		c.compareEvents(args)
+	default:
+		fmt.Fprintf(c.term, "Unknown command: %s. Type help for the list of commands.\n", name)
+	}
+
+	return true
+}
+
+// confirm 询问用户确认，只有输入 y/yes 时返回 true
+func (c *CLI) confirm(question string) bool {
+	c.reader.SetPrompt(question + " [y/N] ")
+	answer, err := c.reader.ReadLine()
+	if err != nil {
+		return false
+	}
+
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
+// splitCommand 将元命令拆分为命令名和参数
+func splitCommand(cmd string) (string, string) {
+	cmd = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(cmd), ";"))
+	if idx := strings.IndexAny(cmd, " \t"); idx >= 0 {
+		return cmd[:idx], strings.TrimSpace(cmd[idx+1:])
+	}
+	return cmd, ""
+}
@@ -0,0 +1,199 @@
+package clickhouse
+
+import (
+	"fmt"
+	"strings"
+)
+
+// completer 实现 readline.AutoCompleter，根据光标前的语句上下文给出候选
+type completer struct {
+	cli *CLI
+}
+
+// Do 返回可插入光标处的候选后缀及当前单词长度
+func (cp *completer) Do(line []rune, pos int) ([][]rune, int) {
+	text := string(line[:pos])
+	word := currentWord(text)
+	before := text[:len(text)-len(word)]
+
+	if prev, ok := settingNamePosition(before); ok {
+		if word == "" && prev != "" {
+			// 设置名已补全后再按 Tab：显示当前值和说明
+			cp.showSetting(prev)
+			return nil, 0
+		}
+		return cp.completeSettings(word), len([]rune(word))
+	}
+	if strings.HasPrefix(strings.TrimSpace(text), "\\") {
+		return nil, 0
+	}
+	return cp.completeSQL(string(line), before, word), len([]rune(word))
+}
+
+// sqlKeywords 补全使用的 SQL 关键字
+var sqlKeywords = []string{
+	"ADD", "ALL", "ALTER", "AND", "ANTI", "ANY", "ARRAY", "AS", "ASC", "ASOF", "ATTACH", "BETWEEN", "BY",
+	"CASE", "CLUSTER", "CODEC", "COLUMN", "COMMENT", "CREATE", "CROSS", "DATABASE", "DATABASES", "DEFAULT",
+	"DELETE", "DESC", "DESCRIBE", "DETACH", "DICTIONARY", "DISTINCT", "DROP", "ELSE", "END", "ENGINE",
+	"EXCEPT", "EXISTS", "EXPLAIN", "FINAL", "FORMAT", "FROM", "FULL", "GLOBAL", "GROUP", "HAVING", "IF",
+	"ILIKE", "IN", "INNER", "INSERT", "INTERSECT", "INTERVAL", "INTO", "IS", "JOIN", "KEY", "KILL", "LEFT",
+	"LIKE", "LIMIT", "MATERIALIZED", "MODIFY", "NOT", "NULL", "OFFSET", "ON", "OPTIMIZE", "OR", "ORDER",
+	"OUTER", "OUTFILE", "PARTITION", "PREWHERE", "PRIMARY", "QUERY", "RENAME", "RIGHT", "SAMPLE", "SELECT",
+	"SEMI", "SET", "SETTINGS", "SHOW", "SYSTEM", "TABLE", "TABLES", "TEMPORARY", "THEN", "TRUNCATE", "TTL",
+	"UNION", "UPDATE", "USE", "USING", "VALUES", "VIEW", "WHEN", "WHERE", "WITH",
+}
+
+// completeSQL 按光标前的上下文补全 SQL：USE 之后为数据库，FROM/JOIN 等之后为当前库的表和数据库，
+// "库." 之后为该库的表，"表." 之后为该表的字段，其余位置为关键字、函数和语句中引用的表的字段
+func (cp *completer) completeSQL(line, before, word string) [][]rune {
+	c := cp.cli
+	tokens := significantTokens(scanSQL(before))
+	online := c.db != nil
+
+	if n := len(tokens); online && n >= 2 && tokens[n-1].text == "." && strings.HasSuffix(before, ".") &&
+		tokens[n-2].kind == tokenWord {
+		qualifier := tokens[n-2].text
+		if tables, err := c.schemaTables(qualifier); err == nil && len(tables) > 0 {
+			return matchCandidates(word, tables, " ")
+		}
+		columns, _ := c.schemaColumns(c.database, qualifier)
+		return matchCandidates(word, columnNames(columns), "")
+	}
+
+	prev := ""
+	if n := len(tokens); n > 0 && tokens[n-1].kind == tokenWord {
+		prev = strings.ToUpper(tokens[n-1].text)
+	}
+	switch {
+	case !online:
+	case prev == "USE" || prev == "DATABASE":
+		databases, _ := c.schemaDatabases()
+		return matchCandidates(word, databases, " ")
+	case prev == "FROM" || prev == "JOIN" || prev == "INTO" || prev == "TABLE" || prev == "UPDATE" ||
+		prev == "DESCRIBE" || prev == "TRUNCATE" || prev == "OPTIMIZE" || (prev == "DESC" && len(tokens) == 1):
+		tables, _ := c.schemaTables(c.database)
+		databases, _ := c.schemaDatabases()
+		return append(matchCandidates(word, tables, " "), matchCandidates(word, databases, ".")...)
+	}
+
+	if word == "" {
+		return nil
+	}
+	keywords := sqlKeywords
+	if strings.ToLower(word) == word {
+		keywords = make([]string, len(sqlKeywords))
+		for i, kw := range sqlKeywords {
+			keywords[i] = strings.ToLower(kw)
+		}
+	}
+	candidates := matchCandidates(word, keywords, " ")
+	if !online {
+		return candidates
+	}
+	for _, ref := range statementTables(line, c.database) {
+		columns, _ := c.schemaColumns(ref[0], ref[1])
+		candidates = append(candidates, matchCandidates(word, columnNames(columns), "")...)
+	}
+	if functions, err := c.schemaFunctions(); err == nil {
+		candidates = append(candidates, matchCandidates(word, functions, "(")...)
+	}
+	return candidates
+}
+
+// matchCandidates 返回 names 中以 prefix 开头（不区分大小写）的名称去掉前缀并加上 suffix 后的候选
+func matchCandidates(prefix string, names []string, suffix string) [][]rune {
+	var candidates [][]rune
+	for _, name := range names {
+		if len(name) >= len(prefix) && strings.EqualFold(name[:len(prefix)], prefix) {
+			candidates = append(candidates, []rune(name[len(prefix):]+suffix))
+		}
+	}
+	return candidates
+}
+
+// columnNames 返回字段名列表
+func columnNames(columns []columnInfo) []string {
+	names := make([]string, len(columns))
+	for i, col := range columns {
+		names[i] = col.name
+	}
+	return names
+}
+
+// statementTables 返回语句中 FROM/JOIN 之后引用的表，每项为 [数据库, 表名]，未限定库名时使用 database
+func statementTables(sqlStr, database string) [][2]string {
+	tokens := significantTokens(scanSQL(sqlStr))
+	var refs [][2]string
+	for i := 0; i+1 < len(tokens); i++ {
+		if !tokens[i].isKeyword("FROM") && !tokens[i].isKeyword("JOIN") || tokens[i+1].kind != tokenWord {
+			continue
+		}
+		ref := [2]string{database, tokens[i+1].text}
+		if i+3 < len(tokens) && tokens[i+2].text == "." && tokens[i+3].kind == tokenWord {
+			ref = [2]string{tokens[i+1].text, tokens[i+3].text}
+		}
+		refs = append(refs, ref)
+	}
+	return refs
+}
+
+// completeSettings 返回以 prefix 开头（不区分大小写）的设置名候选
+func (cp *completer) completeSettings(prefix string) [][]rune {
+	settings, err := cp.cli.schemaSettings()
+	if err != nil {
+		return nil
+	}
+	lower := strings.ToLower(prefix)
+	var candidates [][]rune
+	for _, s := range settings {
+		if strings.HasPrefix(s.name, lower) {
+			candidates = append(candidates, []rune(s.name[len(prefix):]+" "))
+		}
+	}
+	return candidates
+}
+
+// showSetting 在输入行上方显示设置的当前值和说明
+func (cp *completer) showSetting(name string) {
+	settings, err := cp.cli.schemaSettings()
+	if err != nil {
+		return
+	}
+	for _, s := range settings {
+		if strings.EqualFold(s.name, name) {
+			// 在补全回调返回后再输出，避免与 readline 的刷新交错
+			go cp.cli.reader.Print(fmt.Sprintf("%s = %s\n  %s\n", s.name, s.value, s.description))
+			return
+		}
+	}
+}
+
+// currentWord 返回光标前正在输入的标识符
+func currentWord(text string) string {
+	i := len(text)
+	for i > 0 && isWordByte(text[i-1]) {
+		i--
+	}
+	return text[i:]
+}
+
+// settingNamePosition 判断光标是否位于 SET 语句中设置名的位置
+// 返回紧挨着光标的上一个完整设置名（用于第二次 Tab 显示说明），没有时为空
+func settingNamePosition(before string) (string, bool) {
+	tokens := significantTokens(scanSQL(before))
+	if len(tokens) == 0 || !tokens[0].isKeyword("SET") {
+		return "", false
+	}
+
+	last := tokens[len(tokens)-1]
+	switch {
+	case len(tokens) == 1 || last.text == ",":
+		return "", true
+	case last.kind == tokenWord && strings.HasSuffix(before, " "):
+		prevTok := tokens[len(tokens)-2]
+		if prevTok.isKeyword("SET") || prevTok.text == "," {
+			return last.text, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,65 @@
+package clickhouse
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// connectTo 切换到另一个服务器：\connect host[:port] [user] [database]
+// 切换到新的主机或用户时总是重新询问密码（不回显），不会复用旧密码；与 \reconnect 一样保留会话设置和查询参数
+func (c *CLI) connectTo(args string) {
+	fields := strings.Fields(args)
+	if len(fields) == 0 {
+		fmt.Fprintf(c.term, "Usage: \\connect host[:port] [user] [database]\n")
+		return
+	}
+
+	host, port := fields[0], c.port
+	if h, p, err := net.SplitHostPort(fields[0]); err == nil {
+		n, err := strconv.Atoi(p)
+		if err != nil {
+			fmt.Fprintf(c.term, "Invalid port: %s\n", p)
+			return
+		}
+		host, port = h, n
+	}
+
+	username, database := c.username, c.database
+	if len(fields) > 1 {
+		username = fields[1]
+	}
+	if len(fields) > 2 {
+		database = fields[2]
+	}
+
+	password := c.password
+	if host != c.host || username != c.username {
+		var err error
+		password, err = c.reader.ReadPassword(fmt.Sprintf("Password for %s@%s: ", username, host))
+		if err != nil {
+			fmt.Fprintf(c.term, "Cancelled.\n")
+			return
+		}
+	}
+
+	oldDB := c.db
+	oldHost, oldPort, oldSocket, oldHosts, oldUser, oldPassword, oldDatabase := c.host, c.port, c.socket, c.hosts, c.username, c.password, c.database
+	c.host, c.port, c.socket, c.hosts, c.username, c.password, c.database = host, port, "", nil, username, password, database
+	if err := c.open(); err != nil {
+		c.host, c.port, c.socket, c.hosts, c.username, c.password, c.database = oldHost, oldPort, oldSocket, oldHosts, oldUser, oldPassword, oldDatabase
+		c.db = oldDB
+		c.printError(err)
+		return
+	}
+
+	if oldDB != nil {
+		oldDB.Close()
+	}
+	c.page = nil
+	c.schema.reset()
+	c.fetchServerInfo()
+	c.restoreSession()
+	c.showWelcome()
+}
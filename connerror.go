@@ -0,0 +1,59 @@
+package clickhouse
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"syscall"
+)
+
+// unknownDatabaseCode 服务端错误码 UNKNOWN_DATABASE
+const unknownDatabaseCode = 81
+
+// connectError 连接失败时附带修复提示的错误，原始错误通过 Unwrap 保留，
+// 以便调用方仍能按错误码判断
+type connectError struct {
+	hint string
+	err  error
+}
+
+func (e *connectError) Error() string {
+	return e.hint + ": " + strings.TrimSpace(e.err.Error())
+}
+
+func (e *connectError) Unwrap() error {
+	return e.err
+}
+
+// validateAddr 拨号前检查 host 和 port，避免以空地址连接后只得到驱动的底层错误；
+// 套接字和多主机连接不使用 host:port，不做检查
+func (c *CLI) validateAddr() error {
+	if c.socket != "" || c.multiHost() {
+		return nil
+	}
+	if c.host == "" {
+		return errors.New("no host configured: set Config.Host, Config.Hosts or Config.Socket")
+	}
+	if c.port <= 0 || c.port > 65535 {
+		return fmt.Errorf("invalid port %d: use the server's %s", c.port, c.portHint())
+	}
+	return nil
+}
+
+// explainConnectError 将 Ping 失败区分为认证失败、数据库不存在和连接被拒绝，给出应当检查的配置；
+// 其它错误原样返回
+func (c *CLI) explainConnectError(err error) error {
+	exception, ok := serverException(err)
+	switch {
+	case ok && exception.Code == authFailedCode:
+		return &connectError{fmt.Sprintf("authentication failed for user %s at %s (check the username and password or %s)",
+			c.username, c.addr(), passwordEnv), err}
+	case ok && exception.Code == unknownDatabaseCode:
+		return &connectError{fmt.Sprintf("database %s does not exist on %s (check Config.Database or create it first)",
+			c.database, c.addr()), err}
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return &connectError{fmt.Sprintf("connection refused by %s (is the server running, and is this its %s?)",
+			c.addr(), c.portHint()), err}
+	}
+	return err
+}
@@ -0,0 +1,145 @@
+package clickhouse
+
+import (
+	"fmt"
+	"strings"
+)
+
+// setCluster 设置或显示会话默认集群
+func (c *CLI) setCluster(args string) {
+	switch {
+	case args == "":
+		if c.cluster == "" {
+			fmt.Fprintf(c.term, "No cluster set.\n")
+		} else {
+			fmt.Fprintf(c.term, "Cluster: %s\n", c.cluster)
+		}
+	case strings.EqualFold(args, "off"):
+		c.cluster = ""
+		fmt.Fprintf(c.term, "Cluster cleared.\n")
+	default:
+		c.cluster = args
+		fmt.Fprintf(c.term, "Cluster set to %s.\n", c.cluster)
+	}
+}
+
+// copyDDL 输出可在其他环境重放的建表语句
+func (c *CLI) copyDDL(table string) {
+	if table == "" {
+		fmt.Fprintf(c.term, "Usage: \\copy-ddl <table>\n")
+		return
+	}
+
+	ctx, cancel := c.queryContext()
+	defer cancel()
+
+	var ddl string
+	if err := c.db.QueryRowContext(ctx, "SHOW CREATE TABLE "+table).Scan(&ddl); err != nil {
+		c.printError(err)
+		return
+	}
+
+	fmt.Fprintf(c.term, "%s;\n\n", portableDDL(ddl, c.cluster))
+}
+
+// portableDDL 改写 SHOW CREATE 的输出：
+// 添加 IF NOT EXISTS、可选的 ON CLUSTER，并去掉 Atomic/Replicated 库自动生成的 UUID
+func portableDDL(ddl, cluster string) string {
+	tokens := significantTokens(scanSQL(ddl))
+
+	// 定位 CREATE [OR REPLACE] [TEMPORARY] <kind> 之后对象名的位置
+	i := 0
+	if i >= len(tokens) || !tokens[i].isKeyword("CREATE") {
+		return ddl
+	}
+	i++
+	if i+1 < len(tokens) && tokens[i].isKeyword("OR") && tokens[i+1].isKeyword("REPLACE") {
+		i += 2
+	}
+	if i < len(tokens) && tokens[i].isKeyword("TEMPORARY") {
+		i++
+	}
+	for i < len(tokens) && tokens[i].kind == tokenWord && isDDLKindWord(tokens[i].text) {
+		i++
+	}
+	if i >= len(tokens) {
+		return ddl
+	}
+
+	type edit struct {
+		start, end int
+		text       string
+	}
+	var edits []edit
+
+	hasIfNotExists := i+2 < len(tokens) && tokens[i].isKeyword("IF") && tokens[i+1].isKeyword("NOT") && tokens[i+2].isKeyword("EXISTS")
+	if hasIfNotExists {
+		i += 3
+	} else {
+		edits = append(edits, edit{tokens[i].pos, tokens[i].pos, "IF NOT EXISTS "})
+	}
+
+	// 对象名：name 或 db.name
+	nameEnd := i
+	if nameEnd+2 < len(tokens) && tokens[nameEnd+1].text == "." {
+		nameEnd += 2
+	}
+
+	if cluster != "" && !hasTopLevelKeywords(ddl, "ON", "CLUSTER") && nameEnd < len(tokens) {
+		edits = append(edits, edit{tokens[nameEnd].end, tokens[nameEnd].end, " ON CLUSTER " + quoteIdentifier(cluster)})
+	}
+
+	for j := nameEnd + 1; j+1 < len(tokens); j++ {
+		if tokens[j].depth != 0 || !tokens[j].isKeyword("UUID") || tokens[j+1].kind != tokenString {
+			continue
+		}
+		start := tokens[j].pos
+		// 物化视图的 TO INNER UUID '...' 需要整体去掉
+		if j >= 2 && tokens[j-1].isKeyword("INNER") && tokens[j-2].isKeyword("TO") {
+			start = tokens[j-2].pos
+		}
+		end := tokens[j+1].end
+		for end < len(ddl) && ddl[end] == ' ' {
+			end++
+		}
+		edits = append(edits, edit{start, end, ""})
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, e := range edits {
+		b.WriteString(ddl[last:e.start])
+		b.WriteString(e.text)
+		last = e.end
+	}
+	b.WriteString(ddl[last:])
+	return b.String()
+}
+
+// isDDLKindWord 判断是否是 CREATE 后描述对象类型的关键字
+func isDDLKindWord(word string) bool {
+	switch strings.ToUpper(word) {
+	case "TABLE", "VIEW", "MATERIALIZED", "LIVE", "WINDOW", "DICTIONARY", "DATABASE":
+		return true
+	}
+	return false
+}
+
+// quoteIdentifier 在需要时用反引号引用标识符
+func quoteIdentifier(name string) string {
+	for i := 0; i < len(name); i++ {
+		if !isWordByte(name[i]) {
+			return "`" + strings.ReplaceAll(name, "`", "\\`") + "`"
+		}
+	}
+	return name
+}
+
+// unquoteIdentifier 去掉标识符外层的反引号或双引号，并还原转义的引号
+func unquoteIdentifier(name string) string {
+	if len(name) >= 2 && (name[0] == '`' || name[0] == '"') && name[len(name)-1] == name[0] {
+		quote := name[:1]
+		return strings.NewReplacer("\\"+quote, quote, quote+quote, quote).Replace(name[1 : len(name)-1])
+	}
+	return name
+}
@@ -0,0 +1,122 @@
+package clickhouse
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// isExistsResult 判断结果是否是 EXISTS 语句返回的单个 0/1 值
+func isExistsResult(sqlStr string, rs *resultSet) bool {
+	return statementVerb(sqlStr) == "EXISTS" && len(rs.cols) == 1 && len(rs.rows) == 1
+}
+
+// displayExists 将 EXISTS 的 0/1 结果输出为一句话，如 "Table default.t exists."
+func (c *CLI) displayExists(w io.Writer, sqlStr string, rs *resultSet) {
+	kind, name := c.existsTarget(sqlStr)
+	switch formatValue(rs.rows[0][0]) {
+	case "1", "true":
+		fmt.Fprintf(w, "%s %s exists.\n\n", kind, name)
+	default:
+		fmt.Fprintf(w, "%s %s does not exist.\n\n", kind, name)
+	}
+}
+
+// existsTarget 解析 EXISTS [TEMPORARY] [TABLE|DICTIONARY|DATABASE|VIEW] [db.]name，
+// 返回对象种类和名称；表名未限定库时补上当前库
+func (c *CLI) existsTarget(sqlStr string) (string, string) {
+	tokens := significantTokens(scanSQL(sqlStr))
+	for len(tokens) > 0 && !tokens[0].isKeyword("EXISTS") {
+		tokens = tokens[1:]
+	}
+	if len(tokens) > 0 {
+		tokens = tokens[1:]
+	}
+	if len(tokens) > 0 && tokens[0].isKeyword("TEMPORARY") {
+		tokens = tokens[1:]
+	}
+
+	kind := "Table"
+	if len(tokens) > 0 {
+		switch word := strings.ToUpper(tokens[0].text); word {
+		case "TABLE", "DICTIONARY", "DATABASE", "VIEW":
+			kind = word[:1] + strings.ToLower(word[1:])
+			tokens = tokens[1:]
+		}
+	}
+
+	var name strings.Builder
+	for _, t := range tokens {
+		if t.isKeyword("FORMAT") || t.isKeyword("SETTINGS") || t.isKeyword("INTO") {
+			break
+		}
+		name.WriteString(t.text)
+	}
+	target := name.String()
+	if kind != "Database" && c.database != "" && !strings.Contains(target, ".") {
+		target = quoteIdentifier(c.database) + "." + target
+	}
+	return kind, target
+}
+
+// isDescribeResult 判断结果是否是 DESCRIBE 的输出
+func isDescribeResult(sqlStr string, rs *resultSet) bool {
+	switch statementVerb(sqlStr) {
+	case "DESC", "DESCRIBE":
+	default:
+		return false
+	}
+	return len(rs.cols) >= 4 && rs.cols[0] == "name" && rs.cols[1] == "type" &&
+		rs.cols[2] == "default_type" && rs.cols[3] == "default_expression"
+}
+
+// displayDescribe 将 DESCRIBE 的结果整理为 name、type、default、codec 列，默认类型与表达式合并为
+// "DEFAULT now()" 的形式；ttl 和 comment 只在有值时显示。结构信息不截断，列宽按完整内容计算
+func (c *CLI) displayDescribe(w io.Writer, rs *resultSet) {
+	index := make(map[string]int, len(rs.cols))
+	for i, col := range rs.cols {
+		index[col] = i
+	}
+	field := func(row []interface{}, col string) string {
+		if i, ok := index[col]; ok {
+			return formatValue(row[i])
+		}
+		return ""
+	}
+
+	cols := []string{"name", "type", "default", "codec", "ttl", "comment"}
+	cells := make([][]string, len(rs.rows))
+	for n, row := range rs.rows {
+		def := field(row, "default_type")
+		if expr := field(row, "default_expression"); expr != "" {
+			def = strings.TrimSpace(def + " " + expr)
+		}
+		cells[n] = []string{field(row, "name"), field(row, "type"), def,
+			field(row, "codec_expression"), field(row, "ttl_expression"), field(row, "comment")}
+	}
+
+	// 没有任何值的 ttl、comment 列不显示
+	for i := len(cols) - 1; i >= 4; i-- {
+		empty := true
+		for _, row := range cells {
+			empty = empty && row[i] == ""
+		}
+		if !empty {
+			continue
+		}
+		cols = append(cols[:i], cols[i+1:]...)
+		for n, row := range cells {
+			cells[n] = append(row[:i], row[i+1:]...)
+		}
+	}
+
+	widths := make([]int, len(cols))
+	for i, col := range cols {
+		widths[i] = max(utf8.RuneCountInString(col), 4)
+		for _, row := range cells {
+			widths[i] = max(widths[i], utf8.RuneCountInString(row[i]))
+		}
+	}
+	c.renderTable(w, cols, nil, cells, widths)
+}
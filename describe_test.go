@@ -0,0 +1,59 @@
+package clickhouse
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestDisplayExists(t *testing.T) {
+	tests := []struct {
+		sql  string
+		val  interface{}
+		want string
+	}{
+		{"EXISTS TABLE t", uint8(1), "Table default.t exists.\n\n"},
+		{"EXISTS analytics.events", uint8(0), "Table analytics.events does not exist.\n\n"},
+		{"EXISTS DATABASE analytics", uint8(1), "Database analytics exists.\n\n"},
+		{"EXISTS DICTIONARY dict FORMAT TSV", uint8(0), "Dictionary default.dict does not exist.\n\n"},
+	}
+	for _, tt := range tests {
+		var term, out bytes.Buffer
+		c := NewCLI(&term, "localhost", 9000, "default", "", "default")
+		rs := &resultSet{cols: []string{"result"}, types: []string{"UInt8"}, rows: [][]interface{}{{tt.val}}}
+		if !isExistsResult(tt.sql, rs) {
+			t.Errorf("isExistsResult(%q) = false", tt.sql)
+			continue
+		}
+		c.displayExists(&out, tt.sql, rs)
+		if out.String() != tt.want {
+			t.Errorf("displayExists(%q) = %q, want %q", tt.sql, out.String(), tt.want)
+		}
+	}
+}
+
+func TestDisplayDescribe(t *testing.T) {
+	rs := &resultSet{
+		cols: []string{"name", "type", "default_type", "default_expression", "comment", "codec_expression", "ttl_expression"},
+		rows: [][]interface{}{
+			{"id", "UInt64", "", "", "", "", ""},
+			{"created_at", "DateTime", "DEFAULT", "now()", "", "CODEC(Delta(4), ZSTD(1))", ""},
+			{"payload", "String", "", "", "raw event", "", ""},
+		},
+	}
+	var term, out bytes.Buffer
+	c := NewCLI(&term, "localhost", 9000, "default", "", "default")
+	if !isDescribeResult("DESCRIBE TABLE events", rs) {
+		t.Fatal("isDescribeResult = false for a DESCRIBE result")
+	}
+	c.displayDescribe(&out, rs)
+
+	want := "name       │ type     │ default       │ codec                    │ comment  \n" +
+		"───────────┼──────────┼───────────────┼──────────────────────────┼──────────\n" +
+		"id         │ UInt64   │               │                          │          \n" +
+		"created_at │ DateTime │ DEFAULT now() │ CODEC(Delta(4), ZSTD(1)) │          \n" +
+		"payload    │ String   │               │                          │ raw event\n" +
+		"\n"
+	if out.String() != want {
+		t.Errorf("displayDescribe output:\n%s\nwant:\n%s", out.String(), want)
+	}
+}
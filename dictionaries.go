@@ -0,0 +1,51 @@
+package clickhouse
+
+import (
+	"fmt"
+	"time"
+)
+
+// listDictionaries 列出所有字典及其加载状态
+func (c *CLI) listDictionaries() {
+	c.executeSQL(`SELECT database, name, status, element_count, formatReadableSize(bytes_allocated) AS memory, last_exception
+FROM system.dictionaries
+ORDER BY database, name`)
+}
+
+// describeDictionary 显示字典的来源、布局、生命周期和键/属性结构
+func (c *CLI) describeDictionary(name string) {
+	if name == "" {
+		fmt.Fprintf(c.term, "Usage: \\dict <name>\n")
+		return
+	}
+
+	rs, err := c.runQuery(`SELECT database, name, status, origin, type AS layout, source,
+	lifetime_min, lifetime_max, key.names AS key_names, key.types AS key_types,
+	attribute.names AS attribute_names, attribute.types AS attribute_types,
+	element_count, formatReadableSize(bytes_allocated) AS memory,
+	last_successful_update_time, last_exception
+FROM system.dictionaries
+WHERE name = ? OR concat(database, '.', name) = ?`, name, name)
+	if err != nil {
+		c.printError(err)
+		return
+	}
+	if len(rs.rows) == 0 {
+		fmt.Fprintf(c.term, "Dictionary %s not found.\n\n", name)
+		return
+	}
+
+	c.displayVertical(c.term, rs)
+}
+
+// reloadDictionary 执行 SYSTEM RELOAD DICTIONARY
+func (c *CLI) reloadDictionary(name string) {
+	if name == "" {
+		fmt.Fprintf(c.term, "Usage: \\dict-reload <name>\n")
+		return
+	}
+
+	ctx, cancel := c.queryContext()
+	defer cancel()
+	c.executeCommand(ctx, "SYSTEM RELOAD DICTIONARY "+name, time.Now())
+}
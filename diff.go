@@ -0,0 +1,119 @@
+package clickhouse
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxDiffLines 每类差异最多显示的行数
+const maxDiffLines = 20
+
+// markDiffBase 将最近一次查询结果保存为对比基准，下一次查询后输出差异
+func (c *CLI) markDiffBase() {
+	if c.lastResult == nil {
+		fmt.Fprintf(c.term, "No result to compare. Run a query first.\n")
+		return
+	}
+
+	c.diffBase = c.lastResult
+	fmt.Fprintf(c.term, "Stored %d rows as baseline. Run another query to compare.\n", len(c.diffBase.rows))
+}
+
+// printResultDiff 输出基准结果与当前结果的行级差异（按所有列比较）
+func (c *CLI) printResultDiff(base, current *resultSet) {
+	fmt.Fprintf(c.term, "Diff against baseline (%d rows -> %d rows):\n", len(base.rows), len(current.rows))
+	if strings.Join(base.cols, ",") != strings.Join(current.cols, ",") {
+		fmt.Fprintf(c.term, "Warning: columns differ (%s) vs (%s)\n",
+			strings.Join(base.cols, ", "), strings.Join(current.cols, ", "))
+	}
+
+	baseKeys := c.rowKeys(base)
+	currentKeys := c.rowKeys(current)
+
+	counts := make(map[string]int)
+	for _, k := range baseKeys {
+		counts[k]++
+	}
+	var added []string
+	for _, k := range currentKeys {
+		if counts[k] > 0 {
+			counts[k]--
+		} else {
+			added = append(added, k)
+		}
+	}
+	var removed []string
+	for _, k := range baseKeys {
+		if counts[k] > 0 {
+			counts[k]--
+			removed = append(removed, k)
+		}
+	}
+
+	// 第一列相同但其他列不同的行视为修改
+	var changed [][2]string
+	addedByKey := make(map[string][]int)
+	for i, k := range added {
+		first := strings.SplitN(k, "\t", 2)[0]
+		addedByKey[first] = append(addedByKey[first], i)
+	}
+	matched := make(map[int]bool)
+	var onlyRemoved []string
+	for _, k := range removed {
+		first := strings.SplitN(k, "\t", 2)[0]
+		if idx := addedByKey[first]; len(idx) > 0 {
+			changed = append(changed, [2]string{k, added[idx[0]]})
+			matched[idx[0]] = true
+			addedByKey[first] = idx[1:]
+			continue
+		}
+		onlyRemoved = append(onlyRemoved, k)
+	}
+	var onlyAdded []string
+	for i, k := range added {
+		if !matched[i] {
+			onlyAdded = append(onlyAdded, k)
+		}
+	}
+
+	if len(onlyAdded) == 0 && len(onlyRemoved) == 0 && len(changed) == 0 {
+		fmt.Fprintf(c.term, "Results are identical.\n\n")
+		return
+	}
+
+	fmt.Fprintf(c.term, "%d added, %d removed, %d changed.\n", len(onlyAdded), len(onlyRemoved), len(changed))
+	c.printDiffLines("+ ", onlyAdded)
+	c.printDiffLines("- ", onlyRemoved)
+	for i, pair := range changed {
+		if i >= maxDiffLines {
+			fmt.Fprintf(c.term, "~ ... %d more\n", len(changed)-maxDiffLines)
+			break
+		}
+		fmt.Fprintf(c.term, "~ %s\n  -> %s\n", pair[0], pair[1])
+	}
+	fmt.Fprintf(c.term, "\n")
+}
+
+// printDiffLines 输出带前缀的差异行
+func (c *CLI) printDiffLines(prefix string, lines []string) {
+	for i, line := range lines {
+		if i >= maxDiffLines {
+			fmt.Fprintf(c.term, "%s... %d more\n", prefix, len(lines)-maxDiffLines)
+			return
+		}
+		fmt.Fprintf(c.term, "%s%s\n", prefix, line)
+	}
+}
+
+// rowKeys 将每行序列化为以制表符分隔的比较键
+func (c *CLI) rowKeys(rs *resultSet) []string {
+	keys := make([]string, len(rs.rows))
+	for i, vals := range rs.rows {
+		cells := make([]string, len(vals))
+		for j, v := range vals {
+			cells[j] = c.formatCell(v, rs.typeOf(j))
+		}
+		keys[i] = strings.Join(cells, "\t")
+	}
+	return keys
+}
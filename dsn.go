@@ -0,0 +1,192 @@
+package clickhouse
+
+import (
+	"context"
+	"crypto/tls"
+	"database/sql"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+)
+
+// Config 中未设置（零值）时使用的连接默认值
+const (
+	defaultNativePort      = 9000
+	defaultSecurePort      = 9440
+	defaultDialTimeout     = 10 * time.Second
+	defaultReadTimeout     = 30 * time.Second
+	defaultMaxOpenConns    = 10
+	defaultMaxIdleConns    = 5
+	defaultConnMaxLifetime = time.Hour
+)
+
+// dsn 根据当前连接参数和 Config 生成 DSN，HTTP 协议时为 http(s)://，否则为 clickhouse://：
+// 超时、TLS、压缩作为驱动参数，Config.Params 原样追加（驱动将未知参数作为服务端设置发送）
+func (c *CLI) dsn() string {
+	cfg := c.config
+	params := url.Values{}
+	for k, v := range cfg.Params {
+		params.Set(k, v)
+	}
+	params.Set("dial_timeout", durationOr(cfg.DialTimeout, defaultDialTimeout).String())
+	params.Set("read_timeout", durationOr(cfg.ReadTimeout, defaultReadTimeout).String())
+	if cfg.Secure {
+		params.Set("secure", "true")
+		if cfg.SkipVerify {
+			params.Set("skip_verify", "true")
+		}
+	}
+	if compression := strings.ToLower(cfg.Compression); compression != "" && compression != "none" {
+		params.Set("compress", compression)
+	}
+	if c.multiHost() && strings.EqualFold(cfg.HostOrder, hostOrderRoundRobin) {
+		params.Set("connection_open_strategy", hostOrderRoundRobin)
+	}
+
+	host := c.host
+	if c.socket != "" {
+		// 套接字连接由 dialContext 处理，地址只用于满足 DSN 格式
+		host = "localhost"
+	}
+	scheme := "clickhouse"
+	if c.viaHTTP() {
+		scheme = "http"
+		if cfg.Secure {
+			scheme = "https"
+		}
+	}
+	u := url.URL{
+		Scheme:   scheme,
+		User:     url.UserPassword(c.username, c.password),
+		Host:     net.JoinHostPort(host, strconv.Itoa(c.port)),
+		Path:     "/" + c.database,
+		RawQuery: params.Encode(),
+	}
+	return u.String()
+}
+
+// connOptions 解析 DSN 得到驱动参数；Unix 套接字和写超时需要自定义拨号
+func (c *CLI) connOptions() (*clickhouse.Options, error) {
+	if err := c.validateAddr(); err != nil {
+		return nil, err
+	}
+	switch c.protocol {
+	case "", protocolNative, protocolHTTP:
+	default:
+		return nil, fmt.Errorf("unsupported protocol %q: use native or http", c.config.Protocol)
+	}
+	// 驱动会静默忽略不认识的压缩方式，这里提前报错；gzip、deflate、br 只适用于 HTTP 接口
+	switch compression := strings.ToLower(c.config.Compression); {
+	case compression == "", compression == "none", compression == "lz4", compression == "zstd":
+	case c.viaHTTP() && (compression == "gzip" || compression == "deflate" || compression == "br"):
+	case c.viaHTTP():
+		return nil, fmt.Errorf("unsupported compression %q: use lz4, zstd, gzip, deflate, br or none", c.config.Compression)
+	default:
+		return nil, fmt.Errorf("unsupported compression %q: use lz4, zstd or none", c.config.Compression)
+	}
+	if c.socket != "" {
+		if _, err := os.Stat(c.socket); err != nil {
+			return nil, fmt.Errorf("unix socket %s is not available: %w", c.socket, err)
+		}
+	}
+
+	opts, err := clickhouse.ParseDSN(c.dsn())
+	if err != nil {
+		return nil, err
+	}
+	// 多主机地址直接写入 Options（DSN 无法表示多个 IPv6 地址），驱动按 connection_open_strategy 依次尝试，
+	// 并通过自定义拨号记录实际连上的地址
+	if c.multiHost() {
+		opts.Addr = c.hostAddrs()
+	}
+	if c.socket != "" || c.config.WriteTimeout > 0 || c.multiHost() {
+		tlsConfig := opts.TLS
+		if c.viaHTTP() {
+			// HTTP 传输在拨号返回的连接上自行完成 TLS 握手
+			tlsConfig = nil
+		}
+		opts.DialContext = c.dialContext(opts.DialTimeout, tlsConfig)
+	}
+	return opts, nil
+}
+
+// dialContext 返回自定义拨号函数：支持 Unix 套接字，按需建立 TLS，并为每次写入设置超时。
+// 设置 DialContext 后驱动不再自行处理 TLS，因此在这里完成握手
+func (c *CLI) dialContext(timeout time.Duration, tlsConfig *tls.Config) func(context.Context, string) (net.Conn, error) {
+	return func(ctx context.Context, addr string) (net.Conn, error) {
+		network := "tcp"
+		if c.socket != "" {
+			network, addr = "unix", c.socket
+		}
+		d := net.Dialer{Timeout: timeout}
+		conn, err := d.DialContext(ctx, network, addr)
+		if err != nil {
+			return nil, err
+		}
+
+		if tlsConfig != nil {
+			cfg := tlsConfig.Clone()
+			if cfg.ServerName == "" && network == "tcp" {
+				cfg.ServerName, _, _ = net.SplitHostPort(addr)
+			}
+			tlsConn := tls.Client(conn, cfg)
+			if err := tlsConn.HandshakeContext(ctx); err != nil {
+				conn.Close()
+				return nil, err
+			}
+			conn = tlsConn
+		}
+
+		if c.config.WriteTimeout > 0 {
+			conn = &writeTimeoutConn{Conn: conn, timeout: c.config.WriteTimeout}
+		}
+		if network == "tcp" {
+			c.dialedAddr.Store(addr)
+		}
+		return conn, nil
+	}
+}
+
+// writeTimeoutConn 每次写入前设置写超时，驱动本身没有写超时参数
+type writeTimeoutConn struct {
+	net.Conn
+	timeout time.Duration
+}
+
+// Write 在写入前刷新写超时
+func (w *writeTimeoutConn) Write(b []byte) (int, error) {
+	if err := w.Conn.SetWriteDeadline(time.Now().Add(w.timeout)); err != nil {
+		return 0, err
+	}
+	return w.Conn.Write(b)
+}
+
+// applyPoolConfig 按 Config 设置连接池，未设置的项使用默认值
+func (c *CLI) applyPoolConfig(db *sql.DB) {
+	cfg := c.config
+	maxOpen := cfg.MaxOpenConns
+	if maxOpen <= 0 {
+		maxOpen = defaultMaxOpenConns
+	}
+	maxIdle := cfg.MaxIdleConns
+	if maxIdle <= 0 {
+		maxIdle = min(defaultMaxIdleConns, maxOpen)
+	}
+	db.SetMaxOpenConns(maxOpen)
+	db.SetMaxIdleConns(maxIdle)
+	db.SetConnMaxLifetime(durationOr(cfg.ConnMaxLifetime, defaultConnMaxLifetime))
+}
+
+// durationOr 返回 d，d 未设置时返回默认值
+func durationOr(d, fallback time.Duration) time.Duration {
+	if d > 0 {
+		return d
+	}
+	return fallback
+}
@@ -0,0 +1,34 @@
+package clickhouse
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+)
+
+func TestDSNEscapesCredentials(t *testing.T) {
+	tests := []struct {
+		username, password, database string
+	}{
+		{"default", "p@ss:w/rd?#%", "default"},
+		{"user@corp", "100%:a/b?c#d@e", "db/with?odd#name"},
+		{"default", "%2F%40", "default"},
+	}
+	for _, tt := range tests {
+		var term bytes.Buffer
+		c := NewCLI(&term, "localhost", 9000, tt.username, tt.password, tt.database)
+		opts, err := clickhouse.ParseDSN(c.dsn())
+		if err != nil {
+			t.Errorf("ParseDSN(dsn()) for password %q: %v", tt.password, err)
+			continue
+		}
+		if opts.Auth.Username != tt.username || opts.Auth.Password != tt.password || opts.Auth.Database != tt.database {
+			t.Errorf("round trip = %q/%q/%q, want %q/%q/%q", opts.Auth.Username, opts.Auth.Password, opts.Auth.Database,
+				tt.username, tt.password, tt.database)
+		}
+		if len(opts.Addr) != 1 || opts.Addr[0] != "localhost:9000" {
+			t.Errorf("password %q: addr = %v, want [localhost:9000]", tt.password, opts.Addr)
+		}
+	}
+}
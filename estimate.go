@@ -0,0 +1,60 @@
+package clickhouse
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// estimateWarnRows 预估读取行数超过该值时提示全表扫描风险
+const estimateWarnRows = 100_000_000
+
+// sizeEstimate 执行 EXPLAIN ESTIMATE 显示每张表预计读取的 part、行和 mark 数，确认后再执行查询
+func (c *CLI) sizeEstimate(query string) {
+	query = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(query), ";"))
+	if query == "" {
+		fmt.Fprintf(c.term, "Usage: \\size-estimate <query>\n")
+		return
+	}
+
+	rs, err := c.runQuery("EXPLAIN ESTIMATE " + query)
+	if err != nil {
+		c.printError(err)
+		return
+	}
+
+	var parts, rows, marks uint64
+	summary := &resultSet{cols: []string{"table", "parts", "rows", "marks"}}
+	for _, row := range rs.rows {
+		if len(row) < 5 {
+			continue
+		}
+		p, r, m := toUint64(row[2]), toUint64(row[3]), toUint64(row[4])
+		parts, rows, marks = parts+p, rows+r, marks+m
+		summary.rows = append(summary.rows, []interface{}{
+			formatValue(row[0]) + "." + formatValue(row[1]), p, r, m,
+		})
+	}
+	if len(summary.rows) == 0 {
+		fmt.Fprintf(c.term, "No estimate available (the query reads no MergeTree tables).\n")
+	} else {
+		c.displayTable(c.term, summary)
+		fmt.Fprintf(c.term, "Estimated total: %d rows in %d parts (%d marks).\n", rows, parts, marks)
+	}
+	if rows > estimateWarnRows {
+		fmt.Fprintf(c.term, "Warning: this query would read more than %d rows; check its WHERE clause and primary key usage.\n",
+			estimateWarnRows)
+	}
+
+	if !c.confirm("Run the query?") {
+		fmt.Fprintf(c.term, "Not run.\n\n")
+		return
+	}
+	c.executeSQL(query)
+}
+
+// toUint64 将整数列的值转换为 uint64，无法解析时返回 0
+func toUint64(v interface{}) uint64 {
+	n, _ := strconv.ParseUint(formatValue(v), 10, 64)
+	return n
+}
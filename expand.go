@@ -0,0 +1,87 @@
+package clickhouse
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// envRefPattern 匹配 ${VAR} 形式的环境变量引用
+var envRefPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// setExpandEnv 开关 SQL 中的环境变量替换
+func (c *CLI) setExpandEnv(args string) {
+	switch strings.ToLower(args) {
+	case "on":
+		c.expandEnv = true
+	case "off":
+		c.expandEnv = false
+	case "":
+		c.expandEnv = !c.expandEnv
+	default:
+		fmt.Fprintf(c.term, "Usage: \\expandenv [on|off]\n")
+		return
+	}
+
+	if c.expandEnv {
+		fmt.Fprintf(c.term, "Environment variable expansion is on.\n")
+	} else {
+		fmt.Fprintf(c.term, "Environment variable expansion is off.\n")
+	}
+}
+
+// expandEnvVars 将 SQL 中的 ${VAR} 替换为环境变量的值
+// 字符串字面量内的替换值会转义引号和反斜杠，注释中的引用保持不变，未定义的变量保留原样并给出警告
+func (c *CLI) expandEnvVars(sqlStr string) string {
+	if !strings.Contains(sqlStr, "${") {
+		return sqlStr
+	}
+
+	tokens := scanSQL(sqlStr)
+	tokenAt := func(pos int) tokenKind {
+		for _, t := range tokens {
+			if pos >= t.pos && pos < t.end {
+				return t.kind
+			}
+		}
+		return tokenWord
+	}
+
+	warned := make(map[string]bool)
+	var b strings.Builder
+	last := 0
+	for _, m := range envRefPattern.FindAllStringSubmatchIndex(sqlStr, -1) {
+		start, end := m[0], m[1]
+		name := sqlStr[m[2]:m[3]]
+
+		kind := tokenAt(start)
+		if kind == tokenComment {
+			continue
+		}
+
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			if !warned[name] {
+				fmt.Fprintf(c.term, "Warning: environment variable %s is not set.\n", name)
+				warned[name] = true
+			}
+			continue
+		}
+		if kind == tokenString {
+			value = escapeString(value)
+		}
+
+		b.WriteString(sqlStr[last:start])
+		b.WriteString(value)
+		last = end
+	}
+	b.WriteString(sqlStr[last:])
+
+	return b.String()
+}
+
+// escapeString 转义单引号字符串字面量中的特殊字符
+func escapeString(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s)
+}
@@ -0,0 +1,103 @@
+package clickhouse
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// isExplainResult 判断结果是否是 EXPLAIN 的输出：只有一列 explain，每行是缩进的一行文本
+func isExplainResult(rs *resultSet) bool {
+	return len(rs.cols) == 1 && rs.cols[0] == "explain"
+}
+
+// displayExplain 按原样逐行输出 EXPLAIN 结果，保留服务端的缩进结构
+func (c *CLI) displayExplain(w io.Writer, rs *resultSet) {
+	for _, row := range rs.rows {
+		fmt.Fprintf(w, "%s\n", formatValue(row[0]))
+	}
+}
+
+// showAST 执行 EXPLAIN AST 并用树形连接线显示语法树
+func (c *CLI) showAST(query string) {
+	query = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(query), ";"))
+	if query == "" {
+		fmt.Fprintf(c.term, "Usage: \\ast <query>\n")
+		return
+	}
+
+	rs, err := c.runQuery("EXPLAIN AST " + query)
+	if err != nil {
+		c.printError(err)
+		return
+	}
+
+	lines := make([]string, len(rs.rows))
+	for i, row := range rs.rows {
+		lines[i] = formatValue(row[0])
+	}
+	for _, line := range astTree(lines) {
+		fmt.Fprintf(c.term, "%s\n", line)
+	}
+	fmt.Fprintf(c.term, "\n")
+}
+
+// astTree 把按前导空格缩进的行转换为带 ├─ └─ │ 连接线的树
+func astTree(lines []string) []string {
+	type node struct {
+		depth int
+		text  string
+	}
+	nodes := make([]node, len(lines))
+	for i, line := range lines {
+		text := strings.TrimLeft(line, " ")
+		nodes[i] = node{len(line) - len(text), text}
+	}
+
+	// isLast 判断节点之后是否还有同级兄弟节点
+	isLast := func(i int) bool {
+		for j := i + 1; j < len(nodes); j++ {
+			if nodes[j].depth < nodes[i].depth {
+				return true
+			}
+			if nodes[j].depth == nodes[i].depth {
+				return false
+			}
+		}
+		return true
+	}
+
+	type ancestor struct {
+		depth int
+		last  bool
+	}
+	var stack []ancestor
+	out := make([]string, len(nodes))
+	for i, n := range nodes {
+		for len(stack) > 0 && stack[len(stack)-1].depth >= n.depth {
+			stack = stack[:len(stack)-1]
+		}
+
+		var b strings.Builder
+		if len(stack) > 0 {
+			// 根节点不画连接线，其余祖先按是否还有兄弟节点画竖线或空白
+			for _, a := range stack[1:] {
+				if a.last {
+					b.WriteString("   ")
+				} else {
+					b.WriteString("│  ")
+				}
+			}
+			if isLast(i) {
+				b.WriteString("└─ ")
+			} else {
+				b.WriteString("├─ ")
+			}
+		}
+		b.WriteString(n.text)
+		out[i] = b.String()
+
+		stack = append(stack, ancestor{n.depth, isLast(i)})
+	}
+	return out
+}
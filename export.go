@@ -0,0 +1,239 @@
+package clickhouse
+
+import (
+	"bufio"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/encoding/wkt"
+)
+
+// clientFormats 由 CLI 在客户端生成的 FORMAT：原生协议会忽略 FORMAT 子句，
+// 因此这些格式从语句中去掉，按结果逐行输出
+var clientFormats = map[string]bool{
+	"JSON":                  true,
+	"CSV":                   true,
+	"CSVWithNames":          true,
+	"TSV":                   true,
+	"TSVWithNames":          true,
+	"TabSeparated":          true,
+	"TabSeparatedWithNames": true,
+}
+
+// trailingFormat 返回语句末尾顶层 FORMAT <name> 子句的格式名和 FORMAT 关键字的位置，没有时格式名为空。
+// 只看最后两个记号，避免把名为 format 的列或函数当成子句
+func trailingFormat(sqlStr string) (string, int) {
+	tokens := significantTokens(scanSQL(sqlStr))
+	n := len(tokens)
+	if n < 3 {
+		return "", 0
+	}
+	kw, name := tokens[n-2], tokens[n-1]
+	if kw.depth != 0 || !kw.isKeyword("FORMAT") || name.kind != tokenWord || tokens[n-3].text == "." {
+		return "", 0
+	}
+	return name.text, kw.pos
+}
+
+// splitOutputFormat 拆出语句末尾顶层的 FORMAT <name> 子句，
+// 只处理 clientFormats 中的格式（名称不区分大小写），否则原样返回语句和空格式
+func splitOutputFormat(sqlStr string) (string, string) {
+	name, pos := trailingFormat(sqlStr)
+	if name == "" {
+		return sqlStr, ""
+	}
+	for format := range clientFormats {
+		if strings.EqualFold(format, name) {
+			return strings.TrimSpace(sqlStr[:pos]), format
+		}
+	}
+	return sqlStr, ""
+}
+
+// writeFormatted 按客户端格式将结果逐行写入 out（不缓存整个结果，不受 maxRows 限制），返回输出的行数
+func (c *CLI) writeFormatted(out io.Writer, rows *sql.Rows, format string) (int, error) {
+	cols, types, err := resultColumns(rows)
+	if err != nil {
+		return 0, err
+	}
+
+	w := bufio.NewWriter(out)
+	var count int
+	next := sqlRowReader(rows, len(cols))
+	switch format {
+	case "JSON":
+		count, err = writeJSONFormat(w, next, cols, types)
+	case "CSV", "CSVWithNames":
+		count, err = writeDelimited(w, next, cols, types, ",", csvField, c.delimitedNull(), format == "CSVWithNames")
+	case "TSV", "TabSeparated", "TSVWithNames", "TabSeparatedWithNames":
+		count, err = writeDelimited(w, next, cols, types, "\t", escapeTSV, c.delimitedNull(), strings.HasSuffix(format, "WithNames"))
+	default:
+		return 0, fmt.Errorf("format %s is not supported by the client", format)
+	}
+	if flushErr := w.Flush(); err == nil {
+		err = flushErr
+	}
+	return count, err
+}
+
+// rowReader 依次返回结果行，读完时 ok 为 false；查询结果和内存中的结果集都以此输出
+type rowReader func() (vals []interface{}, ok bool, err error)
+
+// sqlRowReader 逐行扫描查询结果，读完时返回 rows.Err()
+func sqlRowReader(rows *sql.Rows, n int) rowReader {
+	return func() ([]interface{}, bool, error) {
+		if !rows.Next() {
+			return nil, false, rows.Err()
+		}
+		vals, err := scanRow(rows, n)
+		return vals, err == nil, err
+	}
+}
+
+// sliceRowReader 依次返回内存中的结果行
+func sliceRowReader(rows [][]interface{}) rowReader {
+	return func() ([]interface{}, bool, error) {
+		if len(rows) == 0 {
+			return nil, false, nil
+		}
+		vals := rows[0]
+		rows = rows[1:]
+		return vals, true, nil
+	}
+}
+
+// writeJSONFormat 以 ClickHouse HTTP 接口的 JSON 格式输出：meta、data（每行一个对象）和 rows
+func writeJSONFormat(w io.Writer, next rowReader, cols, types []string) (int, error) {
+	fmt.Fprintf(w, "{\n\t\"meta\":\n\t[")
+	for i, col := range cols {
+		if i > 0 {
+			fmt.Fprintf(w, ",")
+		}
+		fmt.Fprintf(w, "\n\t\t{\n\t\t\t\"name\": %s,\n\t\t\t\"type\": %s\n\t\t}", jsonString(col), jsonString(types[i]))
+	}
+	fmt.Fprintf(w, "\n\t],\n\n\t\"data\":\n\t[")
+
+	count := 0
+	for {
+		vals, ok, err := next()
+		if err != nil {
+			return count, err
+		}
+		if !ok {
+			break
+		}
+		if count > 0 {
+			fmt.Fprintf(w, ",")
+		}
+		fmt.Fprintf(w, "\n\t\t{")
+		for i, v := range vals {
+			if i > 0 {
+				fmt.Fprintf(w, ",")
+			}
+			fmt.Fprintf(w, "\n\t\t\t%s: %s", jsonString(cols[i]), jsonCell(v, types[i]))
+		}
+		fmt.Fprintf(w, "\n\t\t}")
+		count++
+	}
+
+	fmt.Fprintf(w, "\n\t],\n\n\t\"rows\": %d\n}\n", count)
+	return count, nil
+}
+
+// writeDelimited 逐行输出 CSV/TSV，withNames 时先输出列名行；NULL 输出为 null 参数（不转义）
+func writeDelimited(w io.Writer, next rowReader, cols, types []string, sep string,
+	escape func(string) string, null string, withNames bool) (int, error) {
+	fields := make([]string, len(cols))
+	if withNames {
+		for i, col := range cols {
+			fields[i] = escape(col)
+		}
+		fmt.Fprintf(w, "%s\n", strings.Join(fields, sep))
+	}
+
+	count := 0
+	for {
+		vals, ok, err := next()
+		if !ok {
+			return count, err
+		}
+		for i, v := range vals {
+			if v == nil {
+				fields[i] = null
+			} else {
+				fields[i] = escape(textCell(v, types[i]))
+			}
+		}
+		fmt.Fprintf(w, "%s\n", strings.Join(fields, sep))
+		count++
+	}
+}
+
+// delimitedNull 返回 CSV/TSV 中 NULL 的文本：默认与 clickhouse-client 一样为 \N，便于其他程序读取；
+// 用 \nullvalue 或 Config.NullValue 改过显示文本时使用设置的文本
+func (c *CLI) delimitedNull() string {
+	if c.nullValue == defaultNullValue {
+		return `\N`
+	}
+	return c.nullValue
+}
+
+// textCell 将单元格格式化为 CSV/TSV 文本：时间按列类型输出 ISO 格式，地理类型为 WKT
+func textCell(v interface{}, typ string) string {
+	switch val := v.(type) {
+	case time.Time:
+		return isoTime(val, baseType(typ))
+	case orb.Geometry:
+		if isGeoType(baseType(typ)) {
+			return wkt.MarshalString(val)
+		}
+	}
+	if s, ok := formatComposite(v, typ); ok {
+		return s
+	}
+	return formatValue(v)
+}
+
+// csvField 按 RFC 4180 转义 CSV 字段：包含逗号、引号或换行时加引号，内部引号加倍
+func csvField(s string) string {
+	if !strings.ContainsAny(s, ",\"\r\n") {
+		return s
+	}
+	return `"` + strings.ReplaceAll(s, `"`, `""`) + `"`
+}
+
+// jsonCell 将单元格编码为 JSON：NULL 为 null，数值不加引号，时间为 ISO-8601，地理类型为 WKT
+func jsonCell(v interface{}, typ string) string {
+	switch val := v.(type) {
+	case nil:
+		return "null"
+	case time.Time:
+		if t := baseType(typ); t == "Date" || t == "Date32" {
+			return jsonString(val.Format("2006-01-02"))
+		}
+		return jsonString(val.Format(time.RFC3339Nano))
+	case []byte:
+		return jsonString(string(val))
+	case orb.Geometry:
+		if isGeoType(baseType(typ)) {
+			return jsonString(wkt.MarshalString(val))
+		}
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return jsonString(formatValue(v))
+	}
+	return string(data)
+}
+
+// jsonString 将字符串编码为 JSON 字符串字面量
+func jsonString(s string) string {
+	data, _ := json.Marshal(s)
+	return string(data)
+}
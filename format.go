@@ -0,0 +1,333 @@
+package clickhouse
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/encoding/wkt"
+)
+
+// 表格输出格式
+const (
+	formatPretty        = "Pretty"
+	formatPrettyCompact = "PrettyCompact"
+	formatPrettySpace   = "PrettySpace"
+	formatPrettyPaged   = "PrettyPaged"
+)
+
+// 日期时间显示方式
+const (
+	datetimeISO     = "iso"
+	datetimeEpoch   = "epoch"
+	datetimeEpochMs = "epoch_ms"
+)
+
+// defaultNullValue NULL 的默认显示，与 clickhouse-client 一致，便于和空字符串区分
+const defaultNullValue = "ᴺᵁᴸᴸ"
+
+// outputFormats \format 支持的内置输出格式：表格样式在前，其后是垂直、文本和标记格式
+var outputFormats = []string{formatPrettyCompact, formatPretty, formatPrettySpace, formatPrettyPaged,
+	formatVertical, formatCSV, formatTSV, formatJSON, formatJSONEachRow, formatMarkdown, formatHTML}
+
+// clickhouseFormats 服务端支持的 FORMAT 名称，用于校验 SQL 中的 FORMAT 子句
+var clickhouseFormats = []string{
+	"TabSeparated", "TabSeparatedRaw", "TabSeparatedWithNames", "TabSeparatedWithNamesAndTypes",
+	"TabSeparatedRawWithNames", "TabSeparatedRawWithNamesAndTypes", "TSV", "TSVRaw", "TSVWithNames",
+	"TSVWithNamesAndTypes", "TSVRawWithNames", "TSVRawWithNamesAndTypes", "Template", "TemplateIgnoreSpaces",
+	"CSV", "CSVWithNames", "CSVWithNamesAndTypes", "CustomSeparated", "CustomSeparatedWithNames",
+	"CustomSeparatedWithNamesAndTypes", "SQLInsert", "Values", "Vertical", "JSON", "JSONAsString",
+	"JSONAsObject", "JSONStrings", "JSONColumns", "JSONColumnsWithMetadata", "JSONCompact",
+	"JSONCompactStrings", "JSONCompactColumns", "JSONEachRow", "PrettyJSONEachRow",
+	"JSONEachRowWithProgress", "JSONStringsEachRow", "JSONStringsEachRowWithProgress",
+	"JSONCompactEachRow", "JSONCompactEachRowWithNames", "JSONCompactEachRowWithNamesAndTypes",
+	"JSONCompactStringsEachRow", "JSONCompactStringsEachRowWithNames",
+	"JSONCompactStringsEachRowWithNamesAndTypes", "JSONObjectEachRow", "BSONEachRow", "TSKV",
+	"Pretty", "PrettyNoEscapes", "PrettyMonoBlock", "PrettyNoEscapesMonoBlock", "PrettyCompact",
+	"PrettyCompactNoEscapes", "PrettyCompactMonoBlock", "PrettyCompactNoEscapesMonoBlock", "PrettySpace",
+	"PrettySpaceNoEscapes", "PrettySpaceMonoBlock", "PrettySpaceNoEscapesMonoBlock", "Prometheus",
+	"Protobuf", "ProtobufSingle", "ProtobufList", "Avro", "AvroConfluent", "Parquet", "ParquetMetadata",
+	"Arrow", "ArrowStream", "ORC", "One", "Npy", "RowBinary", "RowBinaryWithNames",
+	"RowBinaryWithNamesAndTypes", "RowBinaryWithDefaults", "Native", "Null", "XML", "CapnProto",
+	"LineAsString", "Regexp", "RawBLOB", "MsgPack", "MySQLDump", "DWARF", "Markdown", "Form",
+}
+
+// setFormat 设置或显示输出格式，table 是默认表格样式 PrettyCompact 的别名
+func (c *CLI) setFormat(name string) {
+	names := c.formatNames()
+	if name == "" {
+		fmt.Fprintf(c.term, "Current format: %s. Available: %s\n", c.format, strings.Join(names, ", "))
+		return
+	}
+	if strings.EqualFold(name, "table") {
+		name = formatPrettyCompact
+	}
+
+	if f, ok := lookupName(name, names); ok {
+		c.format = f
+		fmt.Fprintf(c.term, "Output format set to %s.\n", f)
+		return
+	}
+	fmt.Fprintf(c.term, "Unknown format '%s'.%s Available: %s\n",
+		name, didYouMean(name, names), strings.Join(names, ", "))
+}
+
+// checkFormatClause 校验语句末尾顶层的 FORMAT 子句：大小写不符时改为标准名称，
+// 未知格式时输出错误并返回 false，不把语句发送到服务端。只检查末尾的子句，名为 format 的列或别名不受影响
+func (c *CLI) checkFormatClause(sqlStr string) (string, bool) {
+	name, _ := trailingFormat(sqlStr)
+	if name == "" {
+		return sqlStr, true
+	}
+	canonical, ok := lookupName(name, clickhouseFormats)
+	if !ok {
+		fmt.Fprintf(c.term, "Unknown format '%s'.%s Supported: %s, ...\n\n",
+			name, didYouMean(name, clickhouseFormats), strings.Join(commonFormats, ", "))
+		return sqlStr, false
+	}
+	if canonical != name {
+		tokens := significantTokens(scanSQL(sqlStr))
+		nameTok := tokens[len(tokens)-1]
+		sqlStr = sqlStr[:nameTok.pos] + canonical + sqlStr[nameTok.end:]
+	}
+	return sqlStr, true
+}
+
+// commonFormats 格式错误提示中列出的常用格式
+var commonFormats = []string{"Pretty", "Vertical", "JSON", "JSONEachRow", "CSV", "CSVWithNames", "TSV", "TSVWithNames", "Parquet"}
+
+// lookupName 在 names 中不区分大小写地查找 name，返回标准写法
+func lookupName(name string, names []string) (string, bool) {
+	for _, n := range names {
+		if strings.EqualFold(n, name) {
+			return n, true
+		}
+	}
+	return "", false
+}
+
+// didYouMean 返回 " Did you mean 'X'?" 形式的拼写建议，没有足够接近的候选时返回空字符串
+func didYouMean(name string, candidates []string) string {
+	best, bestDist := "", -1
+	for _, cand := range candidates {
+		d := levenshtein(strings.ToLower(name), strings.ToLower(cand))
+		if bestDist < 0 || d < bestDist {
+			best, bestDist = cand, d
+		}
+	}
+	// 允许的编辑距离随长度增长，避免给很短的输入推荐无关格式
+	if bestDist < 0 || bestDist > max(1, len(name)/3) {
+		return ""
+	}
+	return fmt.Sprintf(" Did you mean '%s'?", best)
+}
+
+// levenshtein 计算两个字符串的编辑距离
+func levenshtein(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+// setDatetimeMode 设置 Date/DateTime 列的显示方式
+func (c *CLI) setDatetimeMode(mode string) {
+	switch strings.ToLower(mode) {
+	case datetimeISO, datetimeEpoch, datetimeEpochMs:
+		c.datetimeMode = strings.ToLower(mode)
+		fmt.Fprintf(c.term, "DateTime display mode set to %s.\n", c.datetimeMode)
+	case "":
+		fmt.Fprintf(c.term, "DateTime display mode: %s. Available: iso, epoch, epoch_ms\n", c.datetimeMode)
+	default:
+		fmt.Fprintf(c.term, "Unknown mode '%s'. Available: iso, epoch, epoch_ms\n", mode)
+	}
+}
+
+// setNullValue 处理 \nullvalue [str]：设置 NULL 的显示文本，用引号包围的参数去掉引号，可设为空字符串
+func (c *CLI) setNullValue(args string) {
+	if args == "" {
+		fmt.Fprintf(c.term, "NULL is shown as %q.\n", c.nullValue)
+		return
+	}
+	if len(args) >= 2 && (args[0] == '\'' || args[0] == '"') && args[len(args)-1] == args[0] {
+		args = args[1 : len(args)-1]
+	}
+	c.nullValue = args
+	fmt.Fprintf(c.term, "NULL will be shown as %q.\n", c.nullValue)
+}
+
+// formatCell 按列类型和会话设置格式化单元格，NULL 显示为 \nullvalue 设置的文本
+func (c *CLI) formatCell(v interface{}, typ string) string {
+	if v == nil {
+		return c.nullValue
+	}
+	if t, ok := v.(time.Time); ok {
+		return c.formatTime(t, baseType(typ))
+	}
+	if g, ok := v.(orb.Geometry); ok && isGeoType(baseType(typ)) {
+		return wkt.MarshalString(g)
+	}
+	if s, ok := formatComposite(v, typ); ok {
+		return s
+	}
+	if s, ok := c.prettyNumber(v, typ); ok {
+		return s
+	}
+	return formatValue(v)
+}
+
+// limitCell 按 \widthlimit 截断 formatCell 的结果，表格和垂直输出共用；\wrap 开启时保留完整值
+func (c *CLI) limitCell(s string) string {
+	if c.widthLimit > 0 && !c.wrapCells {
+		return fitCell(s, c.widthLimit)
+	}
+	return s
+}
+
+// isGeoType 判断是否是 ClickHouse 地理类型，这些类型以 WKT 形式显示，如 POINT(1 2)
+func isGeoType(typ string) bool {
+	switch typ {
+	case "Point", "Ring", "LineString", "MultiLineString", "Polygon", "MultiPolygon":
+		return true
+	}
+	return false
+}
+
+// formatTime 按 \datetime 模式格式化时间：
+// epoch 对 DateTime64 输出毫秒，其余输出秒；epoch_ms 一律输出毫秒
+func (c *CLI) formatTime(t time.Time, typ string) string {
+	isDateTime64 := strings.HasPrefix(typ, "DateTime64")
+
+	switch c.datetimeMode {
+	case datetimeEpoch:
+		if isDateTime64 {
+			return strconv.FormatInt(t.UnixMilli(), 10)
+		}
+		return strconv.FormatInt(t.Unix(), 10)
+	case datetimeEpochMs:
+		return strconv.FormatInt(t.UnixMilli(), 10)
+	}
+
+	return isoTime(t, typ)
+}
+
+// isoTime 按列类型输出 ISO 格式时间：Date 只含日期，DateTime64 保留类型声明的小数位
+func isoTime(t time.Time, typ string) string {
+	switch {
+	case typ == "Date" || typ == "Date32":
+		return t.Format("2006-01-02")
+	case strings.HasPrefix(typ, "DateTime64"):
+		if precision := typeArg(typ); precision > 0 {
+			return t.Format("2006-01-02 15:04:05." + strings.Repeat("0", precision))
+		}
+	}
+	return t.Format("2006-01-02 15:04:05")
+}
+
+// baseType 去掉 Nullable(...) 和 LowCardinality(...) 包装
+func baseType(typ string) string {
+	for _, wrapper := range []string{"Nullable(", "LowCardinality("} {
+		for strings.HasPrefix(typ, wrapper) && strings.HasSuffix(typ, ")") {
+			typ = typ[len(wrapper) : len(typ)-1]
+		}
+	}
+	return typ
+}
+
+// typeArg 返回类型的第一个数字参数，如 DateTime64(3, 'UTC') 返回 3
+func typeArg(typ string) int {
+	open := strings.IndexByte(typ, '(')
+	if open < 0 {
+		return 0
+	}
+	arg := typ[open+1:]
+	if end := strings.IndexAny(arg, ",)"); end >= 0 {
+		arg = arg[:end]
+	}
+	n, _ := strconv.Atoi(strings.TrimSpace(arg))
+	return n
+}
+
+// termWidth 返回终端宽度：优先 $COLUMNS，其次终端本身，默认 120
+func (c *CLI) termWidth() int {
+	if n, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && n > 0 {
+		return n
+	}
+	if w := c.reader.Width(); w > 0 {
+		return w
+	}
+	return 120
+}
+
+// displayPaged 将过宽的表格按列拆分为多页，每页重复第一列作为行标识
+func (c *CLI) displayPaged(w io.Writer, rs *resultSet) {
+	cells, colWidths := c.tableCells(rs)
+	if len(rs.cols) < 2 {
+		c.renderTable(w, rs.cols, rs.types, cells, colWidths)
+		return
+	}
+
+	// 按终端宽度贪心地把第 2 列起的列分组，每页至少一列
+	width := c.termWidth()
+	var pages [][]int
+	var page []int
+	used := colWidths[0]
+	for i := 1; i < len(rs.cols); i++ {
+		if len(page) > 0 && used+3+colWidths[i] > width {
+			pages = append(pages, page)
+			page, used = nil, colWidths[0]
+		}
+		page = append(page, i)
+		used += 3 + colWidths[i]
+	}
+	pages = append(pages, page)
+
+	if len(pages) == 1 {
+		c.renderTable(w, rs.cols, rs.types, cells, colWidths)
+		return
+	}
+
+	for p, idx := range pages {
+		first := idx[0] + 1
+		if p == 0 {
+			first = 1
+		}
+		fmt.Fprintf(w, "Columns %d-%d of %d (page %d/%d)\n", first, idx[len(idx)-1]+1, len(rs.cols), p+1, len(pages))
+
+		idx = append([]int{0}, idx...)
+		pageCols := make([]string, len(idx))
+		pageTypes := make([]string, len(idx))
+		pageWidths := make([]int, len(idx))
+		for j, i := range idx {
+			pageCols[j] = rs.cols[i]
+			pageTypes[j] = rs.typeOf(i)
+			pageWidths[j] = colWidths[i]
+		}
+		pageCells := make([][]string, len(cells))
+		for r, row := range cells {
+			pageCells[r] = make([]string, len(idx))
+			for j, i := range idx {
+				pageCells[r][j] = row[i]
+			}
+		}
+
+		c.renderTable(w, pageCols, pageTypes, pageCells, pageWidths)
+	}
+}
@@ -0,0 +1,74 @@
+package clickhouse
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestCheckFormatClause(t *testing.T) {
+	tests := []struct {
+		sql  string
+		want string
+		ok   bool
+	}{
+		{"SELECT 1", "SELECT 1", true},
+		{"SELECT 1 FORMAT jsoneachrow", "SELECT 1 FORMAT JSONEachRow", true},
+		{"SELECT 1 FORMAT Pretty -- tail", "SELECT 1 FORMAT Pretty -- tail", true},
+		{"SELECT 1 FORMAT Jsn", "SELECT 1 FORMAT Jsn", false},
+		{"SELECT format FROM t", "SELECT format FROM t", true},
+		{"SELECT x AS format FROM t", "SELECT x AS format FROM t", true},
+		{"SELECT * FROM t WHERE format = 'CSV'", "SELECT * FROM t WHERE format = 'CSV'", true},
+		{"SELECT t.format FROM t", "SELECT t.format FROM t", true},
+	}
+	for _, tt := range tests {
+		var term bytes.Buffer
+		c := NewCLI(&term, "localhost", 9000, "default", "", "default")
+		got, ok := c.checkFormatClause(tt.sql)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("checkFormatClause(%q) = %q, %v; want %q, %v (output %q)", tt.sql, got, ok, tt.want, tt.ok, term.String())
+		}
+	}
+}
+
+// TestTableAndVerticalCells 表格和垂直输出对同一个值显示相同的单元格文本
+func TestTableAndVerticalCells(t *testing.T) {
+	long := strings.Repeat("x", 80)
+	tests := []struct {
+		name string
+		typ  string
+		val  interface{}
+		want string
+	}{
+		{"null", "Nullable(String)", nil, defaultNullValue},
+		{"decimal", "Decimal(18, 2)", decimal.RequireFromString("1234567.50"), "1,234,567.5"},
+		{"datetime", "DateTime", time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), "2024-01-02 03:04:05"},
+		{"long", "String", long, long[:defaultWidthLimit-3] + "..."},
+		{"array", "Array(Int32)", []int32{1, 2}, "[1,2]"},
+	}
+	for _, tt := range tests {
+		var term bytes.Buffer
+		c := NewCLI(&term, "localhost", 9000, "default", "", "default")
+		c.groupDigits = true
+		rs := &resultSet{cols: []string{"v"}, types: []string{tt.typ}, rows: [][]interface{}{{tt.val}}}
+
+		cells, _ := c.tableCells(rs)
+		table := cells[0][0]
+
+		var out bytes.Buffer
+		c.displayVertical(&out, rs)
+		var vertical string
+		for _, line := range strings.Split(out.String(), "\n") {
+			if v, ok := strings.CutPrefix(line, "v: "); ok {
+				vertical = v
+			}
+		}
+
+		if table != tt.want || vertical != tt.want {
+			t.Errorf("%s: table cell %q, vertical cell %q; want both %q", tt.name, table, vertical, tt.want)
+		}
+	}
+}
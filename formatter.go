@@ -0,0 +1,204 @@
+package clickhouse
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"io"
+	"sort"
+	"strings"
+)
+
+// 表格样式之外的输出格式，与表格样式一起由 \format 切换
+const (
+	formatVertical    = "Vertical"
+	formatCSV         = "CSV"
+	formatTSV         = "TSV"
+	formatJSON        = "JSON"
+	formatJSONEachRow = "JSONEachRow"
+	formatMarkdown    = "Markdown"
+	formatHTML        = "HTML"
+)
+
+// OutputFormatter 将一次查询的完整结果写到 w：cols 为列名，types 为 ClickHouse 类型，
+// rows 为驱动扫描出的值，NULL 为 nil。内置格式和 RegisterFormatter 注册的格式都通过它输出
+type OutputFormatter interface {
+	Format(w io.Writer, cols, types []string, rows [][]interface{}) error
+}
+
+// OutputFormatterFunc 使普通函数可以作为 OutputFormatter 注册
+type OutputFormatterFunc func(w io.Writer, cols, types []string, rows [][]interface{}) error
+
+// Format 调用 f 本身
+func (f OutputFormatterFunc) Format(w io.Writer, cols, types []string, rows [][]interface{}) error {
+	return f(w, cols, types, rows)
+}
+
+// builtinFormatters 内置输出格式；几种表格样式共用 displayTable，由 tableStyle 按 \format 区分
+var builtinFormatters = map[string]func(c *CLI, w io.Writer, rs *resultSet) error{
+	formatPrettyCompact: (*CLI).formatTable,
+	formatPretty:        (*CLI).formatTable,
+	formatPrettySpace:   (*CLI).formatTable,
+	formatPrettyPaged:   (*CLI).formatPaged,
+	formatVertical:      (*CLI).formatVertical,
+	formatCSV:           (*CLI).formatCSV,
+	formatTSV:           (*CLI).formatTSV,
+	formatJSON:          (*CLI).formatJSON,
+	formatJSONEachRow:   (*CLI).formatJSONEachRow,
+	formatMarkdown:      (*CLI).formatMarkdown,
+	formatHTML:          (*CLI).formatHTML,
+}
+
+// RegisterFormatter 注册自定义输出格式，之后可用 \format <name> 选择；与内置格式同名时替换内置格式。
+// Config.OutputFormat 为该名称时立即生效
+func (c *CLI) RegisterFormatter(name string, f OutputFormatter) {
+	if c.formatters == nil {
+		c.formatters = make(map[string]OutputFormatter)
+	}
+	c.formatters[name] = f
+	if c.config != nil && strings.EqualFold(c.config.OutputFormat, name) {
+		c.format = name
+	}
+}
+
+// formatNames 返回 \format 可选的全部格式名：内置格式在前，自定义格式按名称排序
+func (c *CLI) formatNames() []string {
+	names := append([]string(nil), outputFormats...)
+	var custom []string
+	for name := range c.formatters {
+		if _, ok := lookupName(name, names); !ok {
+			custom = append(custom, name)
+		}
+	}
+	sort.Strings(custom)
+	return append(names, custom...)
+}
+
+// formatResult 按当前 \format 输出完整结果集
+func (c *CLI) formatResult(w io.Writer, rs *resultSet) error {
+	if f, ok := c.formatters[c.format]; ok {
+		return f.Format(w, rs.cols, rs.types, rs.rows)
+	}
+	if format, ok := builtinFormatters[c.format]; ok {
+		return format(c, w, rs)
+	}
+	return c.formatTable(w, rs)
+}
+
+// tableFormat 当前格式是否是内置的表格样式；EXPLAIN、DESCRIBE 等结果只在表格样式下使用专门的布局
+func (c *CLI) tableFormat() bool {
+	if _, ok := c.formatters[c.format]; ok {
+		return false
+	}
+	switch c.format {
+	case formatPrettyCompact, formatPretty, formatPrettySpace, formatPrettyPaged:
+		return true
+	}
+	return false
+}
+
+// streamableFormat 当前格式能否边读取边输出：PrettyPaged 和非表格格式需要完整结果
+func (c *CLI) streamableFormat() bool {
+	return c.tableFormat() && c.format != formatPrettyPaged
+}
+
+// formatTable 以当前表格样式输出
+func (c *CLI) formatTable(w io.Writer, rs *resultSet) error {
+	c.displayTable(w, rs)
+	return nil
+}
+
+// formatPaged 将宽表按列分页输出
+func (c *CLI) formatPaged(w io.Writer, rs *resultSet) error {
+	c.displayPaged(w, rs)
+	return nil
+}
+
+// formatVertical 每行输出为一组 列名: 值
+func (c *CLI) formatVertical(w io.Writer, rs *resultSet) error {
+	c.displayVertical(w, rs)
+	return nil
+}
+
+// formatCSV 按 RFC 4180 输出 CSV，与 FORMAT CSV 一样不带列名行
+func (c *CLI) formatCSV(w io.Writer, rs *resultSet) error {
+	_, err := writeDelimited(w, sliceRowReader(rs.rows), rs.cols, rs.types, ",", csvField, c.delimitedNull(), false)
+	return err
+}
+
+// formatTSV 按 clickhouse-client 的转义规则输出 TSV，不带列名行
+func (c *CLI) formatTSV(w io.Writer, rs *resultSet) error {
+	_, err := writeDelimited(w, sliceRowReader(rs.rows), rs.cols, rs.types, "\t", escapeTSV, c.delimitedNull(), false)
+	return err
+}
+
+// formatJSON 以 ClickHouse 的 JSON 格式输出：meta、data 和 rows
+func (c *CLI) formatJSON(w io.Writer, rs *resultSet) error {
+	_, err := writeJSONFormat(w, sliceRowReader(rs.rows), rs.cols, rs.types)
+	return err
+}
+
+// formatJSONEachRow 每行输出一个 JSON 对象
+func (c *CLI) formatJSONEachRow(w io.Writer, rs *resultSet) error {
+	bw := bufio.NewWriter(w)
+	for _, vals := range rs.rows {
+		bw.WriteString("{")
+		for i, v := range vals {
+			if i > 0 {
+				bw.WriteString(",")
+			}
+			fmt.Fprintf(bw, "%s:%s", jsonString(rs.cols[i]), jsonCell(v, rs.typeOf(i)))
+		}
+		bw.WriteString("}\n")
+	}
+	return bw.Flush()
+}
+
+// formatMarkdown 输出 Markdown 表格，数值列在分隔行中标为右对齐，单元格中的 | 和换行被转义
+func (c *CLI) formatMarkdown(w io.Writer, rs *resultSet) error {
+	escape := func(s string) string {
+		s = strings.ReplaceAll(s, "|", `\|`)
+		return strings.ReplaceAll(s, "\n", "<br>")
+	}
+	right := c.columnAlign(rs.cols, rs.types)
+
+	bw := bufio.NewWriter(w)
+	cells := make([]string, len(rs.cols))
+	for i, col := range rs.cols {
+		cells[i] = escape(col)
+	}
+	fmt.Fprintf(bw, "| %s |\n", strings.Join(cells, " | "))
+	for i := range rs.cols {
+		cells[i] = ":-"
+		if right[i] {
+			cells[i] = "-:"
+		}
+	}
+	fmt.Fprintf(bw, "|%s|\n", strings.Join(cells, "|"))
+	for _, vals := range rs.rows {
+		for i, v := range vals {
+			cells[i] = escape(c.formatCell(v, rs.typeOf(i)))
+		}
+		fmt.Fprintf(bw, "| %s |\n", strings.Join(cells, " | "))
+	}
+	return bw.Flush()
+}
+
+// formatHTML 输出 HTML 表格，列名和值都做 HTML 转义
+func (c *CLI) formatHTML(w io.Writer, rs *resultSet) error {
+	bw := bufio.NewWriter(w)
+	bw.WriteString("<table>\n  <tr>")
+	for _, col := range rs.cols {
+		fmt.Fprintf(bw, "<th>%s</th>", html.EscapeString(col))
+	}
+	bw.WriteString("</tr>\n")
+	for _, vals := range rs.rows {
+		bw.WriteString("  <tr>")
+		for i, v := range vals {
+			fmt.Fprintf(bw, "<td>%s</td>", html.EscapeString(c.formatCell(v, rs.typeOf(i))))
+		}
+		bw.WriteString("</tr>\n")
+	}
+	bw.WriteString("</table>\n")
+	return bw.Flush()
+}
@@ -2,20 +2,24 @@ module binrc.com/dbcli/clickhouse-cli
 
 go 1.21
 
-require github.com/ClickHouse/clickhouse-go/v2 v2.16.0
+require (
+	github.com/ClickHouse/clickhouse-go/v2 v2.16.0
+	github.com/chzyer/readline v1.5.1
+	github.com/google/uuid v1.5.0
+	github.com/paulmach/orb v0.10.0
+	github.com/shopspring/decimal v1.3.1
+	golang.org/x/term v0.15.0
+)
 
 require (
 	github.com/ClickHouse/ch-go v0.58.2 // indirect
 	github.com/andybalholm/brotli v1.0.6 // indirect
 	github.com/go-faster/city v1.0.1 // indirect
 	github.com/go-faster/errors v0.7.0 // indirect
-	github.com/google/uuid v1.5.0 // indirect
 	github.com/klauspost/compress v1.17.4 // indirect
-	github.com/paulmach/orb v0.10.0 // indirect
 	github.com/pierrec/lz4/v4 v4.1.19 // indirect
 	github.com/pkg/errors v0.9.1 // indirect
 	github.com/segmentio/asm v1.2.0 // indirect
-	github.com/shopspring/decimal v1.3.1 // indirect
 	go.opentelemetry.io/otel v1.21.0 // indirect
 	go.opentelemetry.io/otel/trace v1.21.0 // indirect
 	golang.org/x/sys v0.15.0 // indirect
@@ -0,0 +1,63 @@
+package clickhouse
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// rowFilter \grep 设置的客户端行过滤条件，只作用于下一次查询
+type rowFilter struct {
+	re     *regexp.Regexp
+	invert bool
+}
+
+// setGrep 解析 \grep [-v] <pattern>，不带参数时取消待生效的过滤
+func (c *CLI) setGrep(args string) {
+	invert := false
+	if rest, ok := strings.CutPrefix(args, "-v"); ok && (rest == "" || rest[0] == ' ' || rest[0] == '\t') {
+		invert = true
+		args = strings.TrimSpace(rest)
+	}
+
+	if args == "" {
+		if c.grep != nil && !invert {
+			c.grep = nil
+			fmt.Fprintf(c.term, "Row filter cleared.\n")
+			return
+		}
+		fmt.Fprintf(c.term, "Usage: \\grep [-v] <pattern>\n")
+		return
+	}
+
+	re, err := regexp.Compile(args)
+	if err != nil {
+		fmt.Fprintf(c.term, "Invalid pattern: %v\n", err)
+		return
+	}
+
+	c.grep = &rowFilter{re: re, invert: invert}
+	if invert {
+		fmt.Fprintf(c.term, "Next query will hide rows matching /%s/.\n", args)
+	} else {
+		fmt.Fprintf(c.term, "Next query will show only rows matching /%s/.\n", args)
+	}
+}
+
+// filterRows 返回只包含匹配行的新结果集，任意单元格匹配即视为该行匹配
+func (c *CLI) filterRows(rs *resultSet, f *rowFilter) *resultSet {
+	filtered := &resultSet{cols: rs.cols, types: rs.types}
+	for _, row := range rs.rows {
+		matched := false
+		for i, v := range row {
+			if f.re.MatchString(c.formatCell(v, rs.typeOf(i))) {
+				matched = true
+				break
+			}
+		}
+		if matched != f.invert {
+			filtered.rows = append(filtered.rows, row)
+		}
+	}
+	return filtered
+}
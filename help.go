@@ -0,0 +1,465 @@
+package clickhouse
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// commandDoc 单个命令的详细帮助
+type commandDoc struct {
+	usage       string
+	description string
+	example     string
+}
+
+// commandDocs \h <command> 使用的命令帮助，键为命令名（反斜杠命令带 \，SQL 命令为大写关键字）
+var commandDocs = map[string]commandDoc{
+	`\h`: {
+		usage:       `\h [command]`,
+		description: "Show the full help, or detailed help for one command. Partial names list all matching commands.",
+		example:     `\h \grep`,
+	},
+	`\q`: {
+		usage:       `\q | exit | quit`,
+		description: "Exit the client.",
+	},
+	`\timing`: {
+		usage:       `\timing | timing`,
+		description: "Toggle printing the elapsed time and query_id after each statement. The client wall-clock time is followed by\nthe query duration reported by the server, e.g. Elapsed: 0.043 sec (server: 0.031 sec); the difference is\nnetwork transfer and rendering time.",
+	},
+	`\G`: {
+		usage:       `\G | vertical`,
+		description: "Toggle vertical output: one block per row with a line per column. Nested Map/Tuple/JSON values are shown as trees.",
+	},
+	`\transpose`: {
+		usage:       `\transpose`,
+		description: "Toggle transposed output: each row is drawn as a boxed name/value card.",
+	},
+	`\render`: {
+		usage:       `\render server [http_port] | client`,
+		description: "server sends queries through the HTTP interface of the connected host (default port 8123, or Config.HTTPPort;\nhttps with Secure) and writes the output the server produces for the FORMAT clause verbatim, byte for byte\nlike the official client; queries without FORMAT get PrettyCompact (Vertical in vertical mode). client (default) uses the CLI's own renderers over the native protocol.",
+		example:     `\render server 8123`,
+	},
+	`\format`: {
+		usage:       `\format [name]`,
+		description: "Set the output format. Without an argument shows the current format and the available ones.\nPrettyCompact (default, alias table) separates columns with │ and underlines the header; Pretty draws a full box around the table;\nPrettySpace uses only spaces. PrettyPaged splits wide tables into column pages that fit the terminal, repeating the first column.\nVertical prints one name: value block per row. CSV, TSV, JSON and JSONEachRow follow the ClickHouse formats of the same name;\nMarkdown and HTML print a table ready to paste into documents. Formats added with RegisterFormatter are listed too.\nThe startup default can be set with Config.OutputFormat.",
+		example:     `\format Markdown`,
+	},
+	`\pager`: {
+		usage:       `\pager [on|off|auto|command]`,
+		description: "Send results to a pager. on uses $PAGER, or less -SR when it is not set; auto only pages results taller than the screen.\nWith on, table rows are streamed to the pager as they arrive and are not limited to 1000; column widths come from the first 200 rows.\nQuitting the pager early cancels the query. Vertical and other buffered output is paged once rendered.\nOnly available on a local terminal; skipped for FORMAT output, JSON envelopes and redirected stdout. A missing pager falls back to direct output.",
+		example:     `\pager less -S`,
+	},
+	`\output`: {
+		usage:       `\output [text|json]`,
+		description: "Switch between the normal text output and JSON envelopes for programmatic drivers.\nIn json mode every result, affected-row count and error is printed as one line:\n{\"status\":\"ok\",\"meta\":[...],\"rows\":[...],\"elapsed_ms\":...} or {\"status\":\"error\",\"code\":...,\"message\":...}",
+		example:     `\output json`,
+	},
+	`\types`: {
+		usage:       `\types [on|off]`,
+		description: "Show the ClickHouse type of each column on a second header line in table output. Without an argument the\nsetting is toggled. Numeric columns (Int*, UInt*, Float*, Decimal) are right-aligned regardless of this setting.",
+		example:     `\types on`,
+	},
+	`\align`: {
+		usage:       `\align col:left|right[,col:left|right...] | reset`,
+		description: "Override the alignment of the named columns in table output, e.g. to right-align codes or left-align numeric IDs\nin report-style results. Overrides accumulate until \\align reset; \\align alone lists them.",
+		example:     `\align id:left,amount:right`,
+	},
+	`\collapse`: {
+		usage:       `\collapse [on|off|col1,col2]`,
+		description: "Blank repeated consecutive values in grouping columns so sorted reports read like a multi-index.\nWith on the first column is collapsed; a later column is only collapsed while all earlier ones repeat.",
+		example:     `\collapse database,table`,
+	},
+	`\pretty`: {
+		usage:       `\pretty numbers [on|off] [separator]`,
+		description: "Insert grouping separators into Int*, UInt*, Float* and Decimal columns in table and vertical output, e.g.\n1,234,567,890. String columns are never touched and CSV/JSON output stays raw. The separator defaults to a\ncomma (Config.DigitSeparator); without on/off the setting is toggled.",
+		example:     `\pretty numbers on _`,
+	},
+	`\widthlimit`: {
+		usage:       `\widthlimit [n]`,
+		description: "Cap the width of table columns and vertical-mode values at n characters (default 50). Longer values are cut\nwith ... unless \\wrap is on. 0 removes the limit so every value is shown in full.",
+		example:     `\widthlimit 0`,
+	},
+	`\wrap`: {
+		usage:       `\wrap [on|off]`,
+		description: "Wrap values longer than the column width limit onto continuation lines inside their column instead of cutting\nthem; embedded newlines start a new line too. The other columns stay blank on continuation lines so rows and\nborders stay aligned. Without an argument the setting is toggled.",
+		example:     `\wrap on`,
+	},
+	`\color`: {
+		usage:       `\color [on|off|auto]`,
+		description: "Color table output: column names and the header rule in cyan, types and NULLs dimmed, numeric columns in\nyellow. auto (default, also Config.Color) colors only when printing to a terminal and NO_COLOR is not set.\nCSV, JSON and INTO OUTFILE output never contain color codes; the pager gets LESS=-R so colors render.",
+		example:     `\color off`,
+	},
+	`\nullvalue`: {
+		usage:       `\nullvalue [text]`,
+		description: "Set the text shown for NULL in table and vertical output (default ᴺᵁᴸᴸ, also Config.NullValue),\nso NULLs can be told apart from empty strings. Use '' for an empty string. CSV and TSV write \\N unless\na text is set here; JSON output keeps null.",
+		example:     `\nullvalue NULL`,
+	},
+	`\datetime`: {
+		usage:       `\datetime [iso|epoch|epoch_ms]`,
+		description: "Choose how Date/DateTime/DateTime64 columns are shown.\nepoch prints Unix seconds (milliseconds for DateTime64), epoch_ms always prints milliseconds.",
+		example:     `\datetime epoch`,
+	},
+	`\next`: {
+		usage:       `\next | \prev`,
+		description: "Re-run the last SELECT ... LIMIT n [OFFSET m] with the offset moved by one page.\nThe query should have an ORDER BY for stable pages.",
+	},
+	`\prev`: {
+		usage:       `\next | \prev`,
+		description: "Re-run the last SELECT ... LIMIT n [OFFSET m] with the offset moved back by one page.",
+	},
+	`\assert`: {
+		usage:       `\assert [--save] <path>`,
+		description: "Compare the result of the preceding query with a golden file and print PASSED, or FAILED with the differing lines.\n--save writes the current result as the new golden file. Results are stored as canonical TSV\n(column names, types, one line per row), independent of display settings. Use ORDER BY for stable results.\nThe number of failed assertions is available to batch drivers via AssertFailures().",
+		example:     `\assert --save testdata/daily_totals.tsv`,
+	},
+	`\checksum`: {
+		usage:       `\checksum <query>`,
+		description: "Print the row count and an order-independent hash of the query result, to compare data across clusters.\nThe hash is computed server-side as sum(sipHash64(*)); if the server cannot hash the columns\nall rows are read and hashed client-side instead. Only compare checksums computed the same way.",
+		example:     `\checksum SELECT * FROM events WHERE date = today() - 1`,
+	},
+	`\diff-result`: {
+		usage:       `\diff-result`,
+		description: "Store the last result as a baseline; after the next query print the rows that were added and removed.",
+	},
+	`\grep`: {
+		usage:       `\grep [-v] <pattern>`,
+		description: "Show only the rows of the next query where any cell matches the regular expression.\n-v hides matching rows instead. \\grep without a pattern cancels a pending filter.",
+		example:     `\grep -v ^system`,
+	},
+	`\memwarn`: {
+		usage:       `\memwarn [size|off]`,
+		description: "Print a warning after any query whose server-reported peak memory exceeds size.\nSize accepts K, M, G and T suffixes. Off by default.",
+		example:     `\memwarn 40G`,
+	},
+	`\expandenv`: {
+		usage:       `\expandenv [on|off]`,
+		description: "Substitute ${VAR} references in SQL with environment variables before sending.\nValues inside string literals are escaped; undefined variables produce a warning.",
+		example:     `\expandenv on`,
+	},
+	`\copy-ddl`: {
+		usage:       `\copy-ddl <table>`,
+		description: "Print the table DDL in a portable form: IF NOT EXISTS, without UUIDs, with ON CLUSTER when \\cluster is set.",
+		example:     `\copy-ddl default.events`,
+	},
+	`\cluster`: {
+		usage:       `\cluster [name|off]`,
+		description: "Set the session cluster used to add ON CLUSTER clauses to generated DDL.",
+		example:     `\cluster main`,
+	},
+	`\connect`: {
+		usage:       `\connect host[:port] [user] [database]`,
+		description: "Connect to another server. Prompts for the password when the host or user changes; keeps the old connection if the new one fails.\nSET session settings and \\param values are kept; settings the new server does not know are removed with a notice.",
+		example:     `\connect ch2.internal:9000 analyst logs`,
+	},
+	`\reconnect`: {
+		usage:       `\reconnect`,
+		description: "Re-establish the connection with the current host, user and database.\nA statement that failed because the connection could not be opened is retried automatically once after reconnecting;\nif that also fails it is kept, and \\reconnect runs it again once the server is reachable.",
+	},
+	`\limit`: {
+		usage:       `\limit <n>|off`,
+		description: "Append LIMIT n to interactive SELECT/WITH queries that have no LIMIT, so the server stops after n rows\ninstead of streaming everything (unlike the client-side row cap, which only truncates the display).\nQueries with UNION/EXCEPT/INTERSECT or INTO OUTFILE and statements run from scripts are left unchanged.",
+		example:     `\limit 100`,
+	},
+	`\i`: {
+		usage:       `\i [--continue] <path>`,
+		description: "Run the statements of a .sql file one by one (also source <path>). Statements are split on semicolons outside\nstrings and comments; a line starting with a backslash is a meta-command. Each statement is echoed with its number\nand printed with the current output settings. Stops at the first error unless --continue is given;\nCtrl-C cancels the current statement and asks whether to skip it, retry it or abort the file.",
+		example:     `\i migrations/001_init.sql`,
+	},
+	`\watch`: {
+		usage:       `\watch <seconds> <query>`,
+		description: "Clear the screen and re-run the query every few seconds, with a timestamp header, until Ctrl-C.\nResults follow the current display settings (\\G, \\format, \\timing); the pager is off while watching.\nFractional intervals such as 0.5 are allowed.",
+		example:     `\watch 2 SELECT query_id, elapsed, read_rows FROM system.processes`,
+	},
+	`\maxrows`: {
+		usage:       `\maxrows [n]`,
+		description: "Show at most n rows of each result (default 1000, also Config.MaxRows); 0 shows every row.\nThe client stops reading after n rows and notes when the result was capped; use \\limit to make the server stop early.\nWith 0 or a limit above 10000, table output is streamed: rows are printed as they arrive, with column widths taken\nfrom the first 200 rows (longer values are truncated), so huge results start immediately and are never held in memory.",
+		example:     `\maxrows 5000`,
+	},
+	`\timeout`: {
+		usage:       `\timeout [seconds|off]`,
+		description: "Set the client-side timeout for each statement (default 60 seconds, also Config.QueryTimeout).\n0 or off waits indefinitely; press Ctrl-C to cancel a running statement instead.",
+		example:     `\timeout 300`,
+	},
+	`\tail`: {
+		usage:       `\tail [-f] <table> [ts_column] [n]`,
+		description: "Show the latest n rows (default 10) of a table ordered by its timestamp column, oldest first.\nThe first DateTime column (or Date column) is used unless ts_column is given.\n-f keeps polling every 2 seconds for rows newer than the last one shown; press Enter to stop.",
+		example:     `\tail -f logs.events 20`,
+	},
+	`\parts`: {
+		usage:       `\parts <table>`,
+		description: "List the active parts of a MergeTree table from system.parts ordered by modification time:\npartition, name, rows, compressed/uncompressed size and merge level, followed by totals.\nWarns when a partition has more than 150 parts, the usual sign that the table needs OPTIMIZE or bigger inserts.",
+		example:     `\parts default.events`,
+	},
+	`\normalized`: {
+		usage:       `\normalized [on|off]`,
+		description: "After each query, look it up in system.query_log by query_id and print the server's formatted statement\n(normalizeQuery on servers older than 23.10), its normalized_query_hash, and the tables and views it actually used.\nUseful to see how views and aliases were expanded. Each lookup runs SYSTEM FLUSH LOGS first.",
+		example:     `\normalized on`,
+	},
+	`\size-estimate`: {
+		usage:       `\size-estimate <query>`,
+		description: "Run EXPLAIN ESTIMATE and show the parts, rows and marks the query would read per table, with totals.\nWarns above 100M rows, then asks for confirmation before running the query itself.",
+		example:     `\size-estimate SELECT count() FROM logs.events WHERE date = today()`,
+	},
+	`\ast`: {
+		usage:       `\ast <query>`,
+		description: "Run EXPLAIN AST for the query and draw the syntax tree the server returns with tree connectors.\nPlain EXPLAIN statements are printed line by line instead of as a table, keeping their indentation.",
+		example:     `\ast SELECT a, count() FROM t GROUP BY a`,
+	},
+	`\replication`: {
+		usage:       `\replication [table]`,
+		description: "Show every replicated table (or just one) from system.replicas joined with system.replication_queue:\nleader/readonly/session-expired flags, queue size, inserts and merges in queue, future parts, absolute delay\nand the number of queue entries failing with an exception. Each replica gets a health verdict\n(SESSION EXPIRED, READONLY, LAGGING over 300s, FAILING, BACKLOG or OK); for unhealthy replicas\nthe most retried failing queue entries are listed with their last exception.",
+		example:     `\replication default.events_local`,
+	},
+	`\check`: {
+		usage:       `\check <table>`,
+		description: "Run CHECK TABLE and summarize the result per part.",
+		example:     `\check default.events`,
+	},
+	`\l`: {
+		usage:       `\l`,
+		description: "Shortcut for SHOW DATABASES, rendered like any other query.",
+		example:     `\l`,
+	},
+	`\dt`: {
+		usage:       `\dt [database]`,
+		description: "Shortcut for SHOW TABLES. Without an argument the tables of the current database are listed.",
+		example:     `\dt system`,
+	},
+	`\d`: {
+		usage:       `\d <table> | \d+ <table>`,
+		description: "Shortcut for DESCRIBE TABLE; \\d+ runs SHOW CREATE TABLE instead.",
+		example:     `\d+ events`,
+	},
+	`\dicts`: {
+		usage:       `\dicts`,
+		description: "List dictionaries with their status, element count, memory usage and last error.",
+	},
+	`\dict`: {
+		usage:       `\dict <name>`,
+		description: "Show a dictionary's source, layout, lifetime and attribute structure.",
+		example:     `\dict geo.countries`,
+	},
+	`\dict-reload`: {
+		usage:       `\dict-reload <name>`,
+		description: "Run SYSTEM RELOAD DICTIONARY for the dictionary.",
+		example:     `\dict-reload geo.countries`,
+	},
+	`\values`: {
+		usage:       `\values <table>`,
+		description: "Enter rows one column at a time. Each prompt shows the column name and type, and every value is parsed\nby the server right away so a bad value is reported and asked again. An empty value uses the type default\n(NULL for Nullable columns), \\N is NULL. \\done inserts the completed rows, \\cancel discards them.",
+		example:     `\values default.users`,
+	},
+	`\load`: {
+		usage:       `\load <file> INTO <table> FORMAT CSV|CSVWithNames|TSV|TSVWithNames`,
+		description: "Stream a local CSV or TSV file into a table through batched INSERTs (100000 rows per batch).\nFields are converted to the column types on the client; \\N is NULL and empty fields take the column default.\n*WithNames formats map the header row to columns by name. A row that does not match the table stops the load\nwith its line number; earlier batches stay inserted. Quote paths containing spaces with single quotes.",
+		example:     `\load ~/events.csv INTO default.events FORMAT CSVWithNames`,
+	},
+	`\paste`: {
+		usage:       `\paste <table>`,
+		description: "Read pasted TSV or CSV rows until an empty line and insert them in batches.\nIf the first row matches the table's column names it is used as a header.",
+		example:     `\paste default.events`,
+	},
+	`\import-errors`: {
+		usage:       `\import-errors skip|abort|report [path]`,
+		description: "Choose how \\paste handles rows that fail to parse or insert.\nabort (default) stops at the first bad row and disables input_format_allow_errors_num/ratio so the server never drops rows silently.\nskip drops bad rows and reports how many were rejected; report also appends them to path (default rejected.tsv)\nas TSV lines of row number, error and the original fields.",
+		example:     `\import-errors report /tmp/events.rejected.tsv`,
+	},
+	`\join-help`: {
+		usage:       `\join-help`,
+		description: "Show the current values of join_algorithm, join_use_nulls, distributed_product_mode and related settings,\nwhere each value comes from (default, server or session), and what it does.",
+	},
+	`\join-algo`: {
+		usage:       `\join-algo <name>[,<name>...]`,
+		description: "Set join_algorithm for the rest of the session, same as SET join_algorithm = '<name>'.",
+		example:     `\join-algo grace_hash`,
+	},
+	`\settings`: {
+		usage:       `\settings`,
+		description: "List the settings changed with SET in this session. They are sent with every following query,\nbecause pooled connections do not share server-side session state. Remove them with \\unset or SET name = DEFAULT.",
+	},
+	`\unset`: {
+		usage:       `\unset <name> [name ...]|all`,
+		description: "Remove session settings set with SET; following queries use the server's values again.",
+		example:     `\unset max_threads`,
+	},
+	`\param`: {
+		usage:       `\param [<name> <value> | reset]`,
+		description: "Set the value of a {name:Type} placeholder for the following statements. Values are sent as query parameters\nand parsed by the server according to the placeholder type, never spliced into the SQL text.\nWithout arguments the current parameters are listed; reset removes them all.",
+		example:     `\param uid 42`,
+	},
+	`\settings-diff`: {
+		usage:       `\settings-diff`,
+		description: "Show every setting whose value differs from the default, with default and current values side by side.\nThe source column tells server/profile changes apart from settings applied with SET in this session.",
+	},
+	"SET": {
+		usage:       "SET <name> = <value>[, ...]",
+		description: "Change a setting for the rest of the session. The CLI remembers it and sends it with every query.\nSET <name> = DEFAULT forgets the override.\nTab after SET completes setting names; a second Tab after a name shows its current value and description.",
+		example:     "SET max_threads = 8",
+	},
+	`\sql-from-clipboard`: {
+		usage:       `\sql-from-clipboard`,
+		description: "Ask the terminal for its clipboard with the OSC 52 query sequence and put the text into the prompt for editing.\nMulti-line text is joined into one line. Terminals that do not answer within 2 seconds are reported as unsupported.",
+	},
+	`\whoami`: {
+		usage:       `\whoami`,
+		description: "Print currentUser(), the roles from system.current_roles, the current database, the server address\nand whether the user or one of its enabled roles holds ALL ON *.* (unknown when system.grants is not readable).",
+	},
+	`\now`: {
+		usage:       `\now`,
+		description: "Print the server time (now64(3)) with the server timezone, the local client time and the skew between them.\nThe client time is taken at the midpoint of the round trip; skew beyond 1 second is flagged,\nsince it makes filters such as ts > now() behave unexpectedly.",
+	},
+	`\peek`: {
+		usage:       `\peek <query_id>`,
+		description: "Show the full text, user, elapsed time, progress and current/peak memory of a running query from system.processes.\nIf the query already finished, reports how it ended according to system.query_log.",
+		example:     `\peek 4f1c0d8e-2b7a-4c55-9a0e-6c1a2f3e9b10`,
+	},
+	`\kill`: {
+		usage:       `\kill [query_id]`,
+		description: "Run KILL QUERY for one query that keeps running on the server after the client gave up on it.\nWithout an id the last statement run in this session is targeted. Each statement is sent with its own\nquery_id, which is printed under the result footer while \\timing is on.",
+		example:     `\kill 4f1c0d8e-2b7a-4c55-9a0e-6c1a2f3e9b10`,
+	},
+	`\querylog`: {
+		usage:       `\querylog <path>|off`,
+		description: "Append every executed statement to a local file as one JSON line with timestamp, query_id,\nelapsed time, ok/error status and the error message. Each line is written as soon as the statement finishes.\nAlias: \\log-query.",
+		example:     `\querylog ~/incident-2024-05-01.jsonl`,
+	},
+	`\flush-logs`: {
+		usage:       `\flush-logs`,
+		description: "Run SYSTEM FLUSH LOGS so recent entries appear in system.query_log and other log tables.",
+	},
+	`\reload-config`: {
+		usage:       `\reload-config`,
+		description: "Run SYSTEM RELOAD CONFIG to re-read the server configuration files.",
+	},
+	`\drop-cache`: {
+		usage:       `\drop-cache <mark|uncompressed|dns|query|mmap|compiled|filesystem|all>`,
+		description: "Run SYSTEM DROP <name> CACHE after confirmation; all drops every listed cache.\nUseful for cold-cache benchmarks; queries are slower until the cache warms up again.",
+		example:     `\drop-cache mark`,
+	},
+	`\sync-replica`: {
+		usage:       `\sync-replica <table>`,
+		description: "Run SYSTEM SYNC REPLICA and wait until the replica has processed its replication queue.",
+		example:     `\sync-replica default.events_local`,
+	},
+	`\kill-mine`: {
+		usage:       `\kill-mine`,
+		description: "Kill all running queries of the current user after confirmation.",
+	},
+	`\top`: {
+		usage:       `\top [n] [bytes|rows|memory|duration|result] [window]`,
+		description: "List the n heaviest finished queries (default 10) in system.query_log within the window (default 1h;\naccepts Go durations plus days, e.g. 30m, 6h, 2d), sorted by the metric (default bytes read).\nShows time, user, duration, rows and bytes read, peak memory, result size, truncated query text and query_id.",
+		example:     `\top 10 memory 1h`,
+	},
+	`\compare-events`: {
+		usage:       `\compare-events [query_id1 query_id2]`,
+		description: "Compare ProfileEvents counters of two queries from system.query_log. Defaults to the last two statements.",
+	},
+	"USE": {
+		usage:       "USE <database>",
+		description: "Change the current database. The database must exist; every connection of the pool is reopened\nwith it as the default, so unqualified table names in later queries resolve against it.",
+		example:     "USE logs",
+	},
+	"SELECT": {
+		usage:       "SELECT ... [FORMAT <format>]",
+		description: "Query data. Results are shown as a table, limited to the first 1000 rows (see \\maxrows).",
+		example:     "SELECT name, engine FROM system.tables WHERE database = 'default'",
+	},
+	"FORMAT": {
+		usage:       "SELECT ... FORMAT <name>",
+		description: "Ask the server for a specific output format. Names are matched case-insensitively;\nunknown names are rejected before the query is sent, with a suggestion for close misspellings.\nJSON, CSV and TSV are written by the client; any other trailing FORMAT (Pretty, Vertical, JSONEachRow, Markdown, ...)\nruns the query through the HTTP interface (Config.HTTPPort, default 8123) and prints the server's output verbatim.\nThat needs Config.Protocol http or \\render server; over the native protocol such queries are rejected.",
+		example:     "SELECT * FROM system.tables FORMAT JSONEachRow",
+	},
+	"OUTFILE": {
+		usage:       "SELECT ... INTO OUTFILE '<path>' [APPEND|TRUNCATE] [FORMAT <name>]",
+		description: "Write the result to a local file instead of the terminal, then print the row and byte count.\nAn existing file is only written with APPEND or TRUNCATE. Formats: JSON, CSV, CSVWithNames, TSV, TSVWithNames;\nwithout FORMAT, .csv and .json files use CSV and JSON, anything else TabSeparated.",
+		example:     "SELECT * FROM events INTO OUTFILE '/tmp/events.csv' TRUNCATE FORMAT CSVWithNames",
+	},
+	"INSERT": {
+		usage:       "INSERT INTO <table> [(columns)] VALUES (...)",
+		description: "Insert data. See also \\paste for pasted TSV/CSV rows.",
+		example:     "INSERT INTO events (id, name) VALUES (1, 'a')",
+	},
+	"OPTIMIZE": {
+		usage:       "OPTIMIZE TABLE <table> [ON CLUSTER c] [PARTITION p] [FINAL] [DEDUPLICATE]",
+		description: "Schedule a merge of data parts. FINAL merges even if there is a single part.",
+		example:     "OPTIMIZE TABLE events PARTITION 202401 FINAL",
+	},
+	"DESCRIBE": {
+		usage:       "DESCRIBE TABLE <table>",
+		description: "Show column names, types, defaults and codecs. The default kind and expression are merged (DEFAULT now()),\nTTL and comment columns appear only when set, and types are never truncated.",
+		example:     "DESCRIBE TABLE events",
+	},
+	"EXISTS": {
+		usage:       "EXISTS [TEMPORARY] [TABLE|DICTIONARY|DATABASE|VIEW] [db.]name",
+		description: "Check whether an object exists; the 0/1 result is printed as a sentence, e.g. Table default.t exists.",
+		example:     "EXISTS TABLE events",
+	},
+	"TRUNCATE": {
+		usage:       "TRUNCATE TABLE [IF EXISTS] <table> [ON CLUSTER c]",
+		description: "Remove all data from a table.",
+		example:     "TRUNCATE TABLE events",
+	},
+}
+
+// showCommandHelp 输出单个命令的详细帮助；名称不完整时列出所有匹配的命令
+func (c *CLI) showCommandHelp(topic string) {
+	name, ok := lookupCommandDoc(topic)
+	if ok {
+		c.printCommandDoc(name)
+		return
+	}
+
+	matches := matchCommandDocs(topic)
+	switch len(matches) {
+	case 0:
+		fmt.Fprintf(c.term, "No help for '%s'. Type \\h for the list of commands.\n", topic)
+	case 1:
+		c.printCommandDoc(matches[0])
+	default:
+		fmt.Fprintf(c.term, "Commands matching '%s':\n", topic)
+		for _, m := range matches {
+			fmt.Fprintf(c.term, "  %s\n", commandDocs[m].usage)
+		}
+		fmt.Fprintf(c.term, "\n")
+	}
+}
+
+// printCommandDoc 按 用法/说明/示例 格式输出命令帮助
+func (c *CLI) printCommandDoc(name string) {
+	doc := commandDocs[name]
+	fmt.Fprintf(c.term, "Usage: %s\n\n", doc.usage)
+	for _, line := range strings.Split(doc.description, "\n") {
+		fmt.Fprintf(c.term, "  %s\n", line)
+	}
+	if doc.example != "" {
+		fmt.Fprintf(c.term, "\nExample:\n  %s\n", doc.example)
+	}
+	fmt.Fprintf(c.term, "\n")
+}
+
+// lookupCommandDoc 精确查找命令，忽略大小写，反斜杠可省略
+func lookupCommandDoc(topic string) (string, bool) {
+	for _, candidate := range []string{topic, `\` + topic} {
+		for name := range commandDocs {
+			if strings.EqualFold(name, candidate) {
+				return name, true
+			}
+		}
+	}
+	return "", false
+}
+
+// matchCommandDocs 返回名称包含 topic 的命令（忽略大小写和反斜杠），按名称排序
+func matchCommandDocs(topic string) []string {
+	needle := strings.ToLower(strings.TrimPrefix(topic, `\`))
+	var matches []string
+	for name := range commandDocs {
+		if strings.Contains(strings.ToLower(strings.TrimPrefix(name, `\`)), needle) {
+			matches = append(matches, name)
+		}
+	}
+	sort.Strings(matches)
+	return matches
+}
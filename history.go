@@ -0,0 +1,88 @@
+package clickhouse
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// defaultHistoryFile 默认的历史记录文件名（位于用户主目录）
+const defaultHistoryFile = ".clickhouse-cli-history"
+
+// historyLimit 启动时载入的最近历史条数
+const historyLimit = 500
+
+// secretPattern 匹配可能内联了密码的语句（CREATE/ALTER USER ... IDENTIFIED BY 等），这类语句不写入历史文件
+var secretPattern = regexp.MustCompile(`(?i)\b(IDENTIFIED|PASSWORD)\b`)
+
+// historyPath 返回历史记录文件路径，未指定时使用 ~/.clickhouse-cli-history
+func (c *CLI) historyPath() string {
+	if c.historyFile != "" {
+		return expandHome(c.historyFile)
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, defaultHistoryFile)
+}
+
+// loadHistory 将历史文件中最近的记录载入 readline，只在首次连接时执行一次
+// 每行一条记录，多行语句中的换行以 TSV 的转义形式保存
+func (c *CLI) loadHistory() {
+	if c.historyLoaded {
+		return
+	}
+	c.historyLoaded = true
+
+	path := c.historyPath()
+	if path == "" {
+		return
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	var entries []string
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		if line := scanner.Text(); line != "" {
+			entries = append(entries, unescapeTSV(line))
+		}
+	}
+	if len(entries) > historyLimit {
+		entries = entries[len(entries)-historyLimit:]
+	}
+	for _, entry := range entries {
+		c.reader.SaveHistory(entry)
+	}
+}
+
+// addHistory 将一条完整输入加入 readline 历史并追加到历史文件（权限 0600）
+// 内联密码的语句只保留在本次会话的内存历史中
+func (c *CLI) addHistory(entry string) {
+	entry = strings.TrimSpace(entry)
+	if entry == "" {
+		return
+	}
+	c.reader.SaveHistory(entry)
+
+	if secretPattern.MatchString(entry) {
+		return
+	}
+	path := c.historyPath()
+	if path == "" {
+		return
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	f.WriteString(escapeTSV(entry) + "\n")
+}
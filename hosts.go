@@ -0,0 +1,45 @@
+package clickhouse
+
+import (
+	"net"
+	"strconv"
+	"strings"
+)
+
+// 多主机时的连接顺序，对应驱动的 connection_open_strategy
+const (
+	hostOrderInOrder    = "in_order"    // 按配置顺序尝试，前面的主机不可用时才连接后面的（默认）
+	hostOrderRoundRobin = "round_robin" // 新连接轮流使用各个主机
+)
+
+// hostAddrs 返回连接地址列表：配置了 Config.Hosts 时为全部地址（未写端口的使用 Port），否则只有 host:port
+func (c *CLI) hostAddrs() []string {
+	if len(c.hosts) == 0 {
+		return []string{net.JoinHostPort(c.host, strconv.Itoa(c.port))}
+	}
+	addrs := make([]string, len(c.hosts))
+	for i, h := range c.hosts {
+		if _, _, err := net.SplitHostPort(h); err == nil {
+			addrs[i] = h
+		} else {
+			addrs[i] = net.JoinHostPort(h, strconv.Itoa(c.port))
+		}
+	}
+	return addrs
+}
+
+// multiHost 是否配置了多个主机
+func (c *CLI) multiHost() bool {
+	return len(c.hosts) > 1 && c.socket == ""
+}
+
+// connectedAddr 返回多主机时最近一次成功建立连接的地址，尚未连接时返回空字符串
+func (c *CLI) connectedAddr() string {
+	addr, _ := c.dialedAddr.Load().(string)
+	return addr
+}
+
+// hostsDescription 返回全部主机地址，用于欢迎信息
+func (c *CLI) hostsDescription() string {
+	return strings.Join(c.hostAddrs(), ", ")
+}
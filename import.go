@@ -0,0 +1,239 @@
+package clickhouse
+
+import (
+	"encoding/csv"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+)
+
+// insertBatchSize 每条 INSERT 语句包含的最大行数
+const insertBatchSize = 1000
+
+// pasteRows 读取粘贴的 TSV/CSV 数据（空行或 EOF 结束）并批量插入表中
+// 首行与表字段名完全一致时视为表头，按名称映射列，否则按位置映射
+func (c *CLI) pasteRows(table string) {
+	if table == "" {
+		fmt.Fprintf(c.term, "Usage: \\paste <table>\n")
+		return
+	}
+
+	columns, err := c.tableColumns(table)
+	if err != nil {
+		c.printError(err)
+		return
+	}
+
+	fmt.Fprintf(c.term, "Paste TSV or CSV rows, end with an empty line.\n")
+	c.reader.SetPrompt("paste> ")
+	var lines []string
+	for {
+		line, err := c.reader.ReadLine()
+		if err != nil || strings.TrimSpace(line) == "" {
+			break
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) == 0 {
+		fmt.Fprintf(c.term, "No rows pasted.\n\n")
+		return
+	}
+
+	records, err := parsePastedRows(lines)
+	if err != nil {
+		fmt.Fprintf(c.term, "Failed to parse pasted data: %v\n\n", err)
+		return
+	}
+
+	names, records, err := mapPastedColumns(columns, records)
+	if err != nil {
+		fmt.Fprintf(c.term, "%v\n\n", err)
+		return
+	}
+
+	startTime := time.Now()
+	inserted, rejected, err := c.insertValues(table, names, records)
+	if err != nil {
+		c.printError(err)
+		if inserted > 0 {
+			fmt.Fprintf(c.term, "%d rows were inserted before the error.\n\n", inserted)
+		}
+		return
+	}
+
+	fmt.Fprintf(c.term, "Ok. %d rows inserted into %s.", inserted, table)
+	if len(rejected) > 0 {
+		fmt.Fprintf(c.term, " %d rows rejected.", len(rejected))
+	}
+	if c.timingEnabled {
+		fmt.Fprintf(c.term, " Elapsed: %.3f sec.", time.Since(startTime).Seconds())
+	}
+	fmt.Fprintf(c.term, "\n")
+	if len(rejected) > 0 && c.importErrors == importReport {
+		if err := writeRejects(c.rejectPath, rejected); err != nil {
+			fmt.Fprintf(c.term, "Failed to write rejected rows: %v\n", err)
+		} else {
+			fmt.Fprintf(c.term, "Rejected rows and errors appended to %s.\n", c.rejectPath)
+		}
+	}
+	fmt.Fprintf(c.term, "\n")
+}
+
+// parsePastedRows 根据是否包含制表符推断 TSV 或 CSV 并解析
+func parsePastedRows(lines []string) ([][]string, error) {
+	isTSV := false
+	for _, line := range lines {
+		if strings.Contains(line, "\t") {
+			isTSV = true
+			break
+		}
+	}
+
+	if !isTSV {
+		r := csv.NewReader(strings.NewReader(strings.Join(lines, "\n")))
+		r.FieldsPerRecord = -1
+		return r.ReadAll()
+	}
+
+	records := make([][]string, len(lines))
+	for i, line := range lines {
+		fields := strings.Split(line, "\t")
+		for j, f := range fields {
+			if f != `\N` {
+				fields[j] = unescapeTSV(f)
+			}
+		}
+		records[i] = fields
+	}
+	return records, nil
+}
+
+// mapPastedColumns 确定插入的列：首行是表头时按名称，否则按位置取前 N 列
+func mapPastedColumns(columns []columnInfo, records [][]string) ([]string, [][]string, error) {
+	known := make(map[string]bool, len(columns))
+	for _, col := range columns {
+		known[col.name] = true
+	}
+
+	header := records[0]
+	isHeader := len(records) > 1
+	for _, name := range header {
+		if !known[strings.TrimSpace(name)] {
+			isHeader = false
+			break
+		}
+	}
+
+	if isHeader {
+		names := make([]string, len(header))
+		for i, name := range header {
+			names[i] = strings.TrimSpace(name)
+		}
+		return names, records[1:], nil
+	}
+
+	width := len(header)
+	if width > len(columns) {
+		return nil, nil, fmt.Errorf("pasted rows have %d fields but the table has %d columns", width, len(columns))
+	}
+	names := make([]string, width)
+	for i := range names {
+		names[i] = columns[i].name
+	}
+	return names, records, nil
+}
+
+// insertValues 以 INSERT ... VALUES 分批插入文本值，返回成功插入的行数和被拒绝的行
+// 值以字符串字面量发送，由服务端按列类型解析；\N 表示 NULL。
+// abort 模式下遇到坏行即返回错误；skip/report 模式下批次失败时逐行重试以找出坏行
+func (c *CLI) insertValues(table string, names []string, records [][]string) (int, []rejectedRow, error) {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = quoteIdentifier(name)
+	}
+	prefix := fmt.Sprintf("INSERT INTO %s (%s) VALUES ", table, strings.Join(quoted, ", "))
+
+	inserted := 0
+	var rejected []rejectedRow
+	for start := 0; start < len(records); start += insertBatchSize {
+		end := min(start+insertBatchSize, len(records))
+
+		var batch [][]string
+		var lineNums []int
+		for i, record := range records[start:end] {
+			if len(record) != len(names) {
+				err := fmt.Errorf("row %d has %d fields, expected %d", start+i+1, len(record), len(names))
+				if c.importErrors == importAbort {
+					return inserted, rejected, err
+				}
+				rejected = append(rejected, rejectedRow{start + i + 1, record, err.Error()})
+				continue
+			}
+			batch = append(batch, record)
+			lineNums = append(lineNums, start+i+1)
+		}
+		if len(batch) == 0 {
+			continue
+		}
+
+		err := c.execValues(prefix, batch)
+		if err == nil {
+			inserted += len(batch)
+			continue
+		}
+		if c.importErrors == importAbort || !isServerException(err) {
+			return inserted, rejected, err
+		}
+		for i, record := range batch {
+			switch err := c.execValues(prefix, [][]string{record}); {
+			case err == nil:
+				inserted++
+			case isServerException(err):
+				rejected = append(rejected, rejectedRow{lineNums[i], record, err.Error()})
+			default:
+				return inserted, rejected, err
+			}
+		}
+	}
+
+	return inserted, rejected, nil
+}
+
+// execValues 执行一条包含 records 的 INSERT ... VALUES 语句
+func (c *CLI) execValues(prefix string, records [][]string) error {
+	var b strings.Builder
+	b.WriteString(prefix)
+	for i, record := range records {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString("(")
+		for j, v := range record {
+			if j > 0 {
+				b.WriteString(", ")
+			}
+			if v == `\N` {
+				b.WriteString("NULL")
+			} else {
+				b.WriteString("'" + escapeString(v) + "'")
+			}
+		}
+		b.WriteString(")")
+	}
+
+	ctx, cancel := c.queryContext()
+	defer cancel()
+	ctx = clickhouse.Context(ctx, clickhouse.WithSettings(c.importSettings()))
+	_, err := c.db.ExecContext(ctx, b.String())
+	return err
+}
+
+// unescapeTSV 还原 TSV 中的转义序列
+func unescapeTSV(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	return strings.NewReplacer(`\t`, "\t", `\n`, "\n", `\r`, "\r", `\\`, `\`, `\0`, "\x00").Replace(s)
+}
@@ -0,0 +1,95 @@
+package clickhouse
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+)
+
+// 导入时坏行的处理方式
+const (
+	importAbort  = "abort"  // 遇到第一个坏行即停止（默认）
+	importSkip   = "skip"   // 丢弃坏行并计数
+	importReport = "report" // 丢弃坏行并连同错误写入文件
+)
+
+// defaultRejectPath report 模式未指定文件时使用的文件名
+const defaultRejectPath = "rejected.tsv"
+
+// rejectedRow 导入时被拒绝的一行
+type rejectedRow struct {
+	line   int
+	record []string
+	reason string
+}
+
+// setImportErrors 处理 \import-errors skip|abort|report [path]
+func (c *CLI) setImportErrors(args string) {
+	fields := strings.Fields(args)
+	if len(fields) == 0 {
+		fmt.Fprintf(c.term, "Import error mode: %s", c.importErrors)
+		if c.importErrors == importReport {
+			fmt.Fprintf(c.term, " (rejected rows go to %s)", c.rejectPath)
+		}
+		fmt.Fprintf(c.term, "\n")
+		return
+	}
+
+	switch mode := strings.ToLower(fields[0]); mode {
+	case importAbort, importSkip:
+		c.importErrors = mode
+		fmt.Fprintf(c.term, "Import error mode set to %s.\n", mode)
+	case importReport:
+		c.importErrors = mode
+		c.rejectPath = defaultRejectPath
+		if len(fields) > 1 {
+			c.rejectPath = fields[1]
+		}
+		fmt.Fprintf(c.term, "Import error mode set to report; rejected rows go to %s.\n", c.rejectPath)
+	default:
+		fmt.Fprintf(c.term, "Usage: \\import-errors skip|abort|report [path]\n")
+	}
+}
+
+// importSettings 返回导入语句的设置：abort 模式显式关闭 input_format_allow_errors_*，
+// 避免会话中 SET 的容错设置让服务端静默丢行；skip/report 由客户端逐行定位坏行
+func (c *CLI) importSettings() clickhouse.Settings {
+	settings := c.sessionSettings()
+	if c.importErrors == importAbort {
+		settings["input_format_allow_errors_num"] = 0
+		settings["input_format_allow_errors_ratio"] = 0
+	}
+	return settings
+}
+
+// isServerException 判断错误是否来自服务端（数据错误），而不是连接等客户端错误
+func isServerException(err error) bool {
+	_, ok := serverException(err)
+	return ok
+}
+
+// writeRejects 以 TSV 形式追加写入被拒绝的行：行号、错误、原始字段
+func writeRejects(path string, rejected []rejectedRow) error {
+	f, err := os.OpenFile(expandHome(path), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	for _, r := range rejected {
+		fields := make([]string, 0, len(r.record)+2)
+		fields = append(fields, fmt.Sprint(r.line), escapeTSV(r.reason))
+		for _, v := range r.record {
+			if v != `\N` {
+				v = escapeTSV(v)
+			}
+			fields = append(fields, v)
+		}
+		if _, err := fmt.Fprintf(f, "%s\n", strings.Join(fields, "\t")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
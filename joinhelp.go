@@ -0,0 +1,90 @@
+package clickhouse
+
+import (
+	"fmt"
+	"strings"
+)
+
+// joinSettings \join-help 展示的 JOIN 相关设置及说明
+var joinSettings = []struct {
+	name string
+	help string
+}{
+	{"join_algorithm", "Algorithm for JOIN: hash, parallel_hash, grace_hash, partial_merge, full_sorting_merge, direct, auto, default.\nThe right table must fit in memory for hash; partial_merge/grace_hash spill to disk but are slower."},
+	{"join_use_nulls", "0: unmatched rows get the column type's default (0, ''), 1: they get NULL like standard SQL.\nWith 0, LEFT JOIN results can silently look like real zero values."},
+	{"join_default_strictness", "Strictness when JOIN has no ALL/ANY: ALL returns every match, ANY only the first.\nEmpty makes a bare JOIN an error."},
+	{"any_join_distinct_right_table_keys", "Legacy ANY JOIN semantics; enabling it changes which rows ANY INNER JOIN returns."},
+	{"distributed_product_mode", "How subqueries over Distributed tables inside IN/JOIN are rewritten: deny, local, global, allow.\ndeny (default) rejects them; global sends the subquery result to every shard."},
+	{"max_bytes_in_join", "Memory limit for the right-side hash table (0 = unlimited)."},
+	{"max_rows_in_join", "Row limit for the right-side hash table (0 = unlimited)."},
+	{"join_overflow_mode", "What to do when a join limit is hit: throw, or break to return a partial result."},
+}
+
+// joinAlgorithms join_algorithm 的可选值
+var joinAlgorithms = []string{
+	"default", "auto", "hash", "parallel_hash", "grace_hash", "partial_merge",
+	"prefer_partial_merge", "full_sorting_merge", "direct",
+}
+
+// joinHelp 显示 JOIN 相关设置的当前值、来源及说明
+func (c *CLI) joinHelp() {
+	names := make([]interface{}, len(joinSettings))
+	for i, s := range joinSettings {
+		names[i] = s.name
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(names)), ", ")
+
+	rs, err := c.runQuery("SELECT name, value, changed FROM system.settings WHERE name IN ("+placeholders+")", names...)
+	if err != nil {
+		c.printError(err)
+		return
+	}
+	values := make(map[string][2]string, len(rs.rows))
+	for _, row := range rs.rows {
+		name := formatValue(row[0])
+		source := "default"
+		if _, ok := c.settings[name]; ok {
+			source = "session"
+		} else if formatValue(row[2]) != "0" && formatValue(row[2]) != "false" {
+			source = "server"
+		}
+		values[name] = [2]string{formatValue(row[1]), source}
+	}
+
+	for _, s := range joinSettings {
+		v, ok := values[s.name]
+		if !ok {
+			continue
+		}
+		fmt.Fprintf(c.term, "%s = %s (%s)\n", s.name, v[0], v[1])
+		for _, line := range strings.Split(s.help, "\n") {
+			fmt.Fprintf(c.term, "    %s\n", line)
+		}
+	}
+	fmt.Fprintf(c.term, "\nUse \\join-algo <name> to change join_algorithm for this session.\n\n")
+}
+
+// setJoinAlgorithm 处理 \join-algo <name>：为本会话设置 join_algorithm
+func (c *CLI) setJoinAlgorithm(args string) {
+	if args == "" {
+		fmt.Fprintf(c.term, "Usage: \\join-algo <name>. Available: %s\n", strings.Join(joinAlgorithms, ", "))
+		return
+	}
+	for _, algo := range strings.Split(args, ",") {
+		if !containsFold(joinAlgorithms, strings.TrimSpace(algo)) {
+			fmt.Fprintf(c.term, "Unknown join algorithm '%s'. Available: %s\n", strings.TrimSpace(algo), strings.Join(joinAlgorithms, ", "))
+			return
+		}
+	}
+	c.executeSQL("SET join_algorithm = '" + escapeString(strings.ToLower(args)) + "'")
+}
+
+// containsFold 判断列表中是否包含 s（不区分大小写）
+func containsFold(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,146 @@
+package clickhouse
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// defaultMaxRows 查询结果默认最多显示的行数
+const defaultMaxRows = 1000
+
+// setMaxRows 处理 \maxrows [n]：设置查询结果最多读取并显示的行数，0 表示不限制
+// 与 \limit 不同，服务端仍会执行完整查询，只是客户端在 n 行后停止读取
+func (c *CLI) setMaxRows(args string) {
+	if args == "" {
+		if c.maxRows > 0 {
+			fmt.Fprintf(c.term, "Max rows: %d\n", c.maxRows)
+		} else {
+			fmt.Fprintf(c.term, "Max rows: unlimited\n")
+		}
+		return
+	}
+
+	n, err := strconv.Atoi(args)
+	if err != nil || n < 0 {
+		fmt.Fprintf(c.term, "Usage: \\maxrows <n> (0 for unlimited)\n")
+		return
+	}
+	c.maxRows = n
+	if n == 0 {
+		fmt.Fprintf(c.term, "Showing all rows of each result.\n")
+	} else {
+		fmt.Fprintf(c.term, "Showing at most %d rows of each result.\n", n)
+	}
+}
+
+// setAutoLimit 处理 \limit <n>|off：为交互式 SELECT 自动追加 LIMIT
+func (c *CLI) setAutoLimit(args string) {
+	switch strings.ToLower(args) {
+	case "":
+		if c.autoLimit > 0 {
+			fmt.Fprintf(c.term, "Automatic LIMIT: %d\n", c.autoLimit)
+		} else {
+			fmt.Fprintf(c.term, "Automatic LIMIT is off.\n")
+		}
+		return
+	case "off", "0":
+		c.autoLimit = 0
+		fmt.Fprintf(c.term, "Automatic LIMIT disabled.\n")
+		return
+	}
+
+	n, err := strconv.Atoi(args)
+	if err != nil || n < 0 {
+		fmt.Fprintf(c.term, "Usage: \\limit <n>|off\n")
+		return
+	}
+	c.autoLimit = n
+	c.limitWarned = false
+	fmt.Fprintf(c.term, "SELECTs without LIMIT will get LIMIT %d.\n", n)
+}
+
+// applyAutoLimit 为没有 LIMIT 的交互式 SELECT 追加 LIMIT，首次生效时提示一次；追加后记录在 limitAdded 中
+func (c *CLI) applyAutoLimit(sqlStr string) string {
+	limited, ok := appendLimit(sqlStr, c.autoLimit)
+	if !ok {
+		return sqlStr
+	}
+	c.limitAdded = true
+	if !c.limitWarned && !c.jsonOutput() {
+		fmt.Fprintf(c.term, "Note: LIMIT %d appended to queries without one (\\limit off to disable).\n", c.autoLimit)
+		c.limitWarned = true
+	}
+	return limited
+}
+
+// appendLimit 在 SELECT/WITH 查询顶层的 SETTINGS/FORMAT 子句之前插入 LIMIT n。
+// 已有 LIMIT、包含 UNION/EXCEPT/INTERSECT（LIMIT 只作用于最后一个子查询）或 INTO OUTFILE 时不改写
+func appendLimit(sqlStr string, n int) (string, bool) {
+	if kw := firstKeyword(sqlStr); kw != "SELECT" && kw != "WITH" {
+		return sqlStr, false
+	}
+
+	insertAt := len(sqlStr)
+	if name, pos := trailingFormat(sqlStr); name != "" {
+		insertAt = pos
+	}
+	tokens := significantTokens(scanSQL(sqlStr))
+	for i, t := range tokens {
+		if t.depth != 0 {
+			continue
+		}
+		switch {
+		case t.isKeyword("LIMIT"), t.isKeyword("UNION"), t.isKeyword("EXCEPT"),
+			t.isKeyword("INTERSECT"), t.isKeyword("INTO"):
+			return sqlStr, false
+		case t.isKeyword("SETTINGS") && t.pos < insertAt && settingsClause(tokens, i):
+			insertAt = t.pos
+			// SETTINGS 也可以写在 FORMAT <name> 之后，此时插入到 FORMAT 之前
+			if i >= 3 && tokens[i-2].depth == 0 && tokens[i-2].isKeyword("FORMAT") && tokens[i-3].text != "." {
+				insertAt = tokens[i-2].pos
+			}
+		}
+	}
+
+	limited := strings.TrimRight(sqlStr[:insertAt], " \t\r\n") + fmt.Sprintf(" LIMIT %d", n)
+	if insertAt < len(sqlStr) {
+		limited += " " + sqlStr[insertAt:]
+	}
+	return limited, true
+}
+
+// expressionKeywords 之后还需要表达式或名称的关键字，紧跟其后的 settings 只能是标识符
+var expressionKeywords = map[string]bool{
+	"SELECT": true, "DISTINCT": true, "FROM": true, "JOIN": true, "AS": true, "WHERE": true, "PREWHERE": true,
+	"AND": true, "OR": true, "NOT": true, "BY": true, "HAVING": true, "ON": true, "USING": true, "IN": true,
+	"IS": true, "LIKE": true, "ILIKE": true, "BETWEEN": true, "CASE": true, "WHEN": true, "THEN": true,
+	"ELSE": true, "WITH": true, "ARRAY": true, "SAMPLE": true,
+}
+
+// settingsClause 判断顶层的 tokens[i]（SETTINGS）是否是 SETTINGS 子句的开头而不是名为 settings 的表或列：
+// 前面是完整的表达式（不是 .、逗号、运算符或 FROM/AS 等关键字），后面是 name = value，且之后没有顶层 FROM（不在 SELECT 列表中）
+func settingsClause(tokens []sqlToken, i int) bool {
+	if i == 0 || i+2 >= len(tokens) {
+		return false
+	}
+	switch prev := tokens[i-1]; prev.kind {
+	case tokenPunct:
+		if prev.text != ")" {
+			return false
+		}
+	case tokenWord:
+		if expressionKeywords[strings.ToUpper(prev.text)] {
+			return false
+		}
+	}
+	if name := tokens[i+1]; name.kind != tokenWord && name.kind != tokenQuoted || tokens[i+2].text != "=" {
+		return false
+	}
+	for _, t := range tokens[i+1:] {
+		if t.depth == 0 && t.isKeyword("FROM") {
+			return false
+		}
+	}
+	return true
+}
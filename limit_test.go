@@ -0,0 +1,59 @@
+package clickhouse
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestAutoLimitIsNotPageable(t *testing.T) {
+	tests := []struct {
+		sql      string
+		pageable bool
+	}{
+		{"SELECT * FROM t", false},
+		{"SELECT * FROM t ORDER BY id", false},
+		{"SELECT * FROM t ORDER BY id LIMIT 10", true},
+	}
+	for _, tt := range tests {
+		var term bytes.Buffer
+		c := NewCLI(&term, "localhost", 9000, "default", "", "default")
+		c.autoLimit = 100
+		c.limitAdded = false
+
+		c.trackPage(c.applyAutoLimit(tt.sql))
+		if (c.page != nil) != tt.pageable {
+			t.Errorf("%q: pageable = %v, want %v", tt.sql, c.page != nil, tt.pageable)
+		}
+		if strings.Contains(term.String(), "no ORDER BY") {
+			t.Errorf("%q: unexpected ORDER BY warning %q", tt.sql, term.String())
+		}
+	}
+}
+
+func TestAppendLimit(t *testing.T) {
+	tests := []struct {
+		sql  string
+		want string
+		ok   bool
+	}{
+		{"SELECT * FROM t", "SELECT * FROM t LIMIT 100", true},
+		{"SELECT * FROM t SETTINGS max_threads = 1", "SELECT * FROM t LIMIT 100 SETTINGS max_threads = 1", true},
+		{"SELECT * FROM t FORMAT JSON", "SELECT * FROM t LIMIT 100 FORMAT JSON", true},
+		{"SELECT * FROM t FORMAT JSON SETTINGS max_threads = 1", "SELECT * FROM t LIMIT 100 FORMAT JSON SETTINGS max_threads = 1", true},
+		{"SELECT * FROM system.settings", "SELECT * FROM system.settings LIMIT 100", true},
+		{"SELECT * FROM settings", "SELECT * FROM settings LIMIT 100", true},
+		{"SELECT format FROM t", "SELECT format FROM t LIMIT 100", true},
+		{"SELECT settings, x FROM t", "SELECT settings, x FROM t LIMIT 100", true},
+		{"SELECT * FROM t WHERE x IN (SELECT y FROM u SETTINGS max_threads = 1)", "SELECT * FROM t WHERE x IN (SELECT y FROM u SETTINGS max_threads = 1) LIMIT 100", true},
+		{"SELECT * FROM t LIMIT 5", "SELECT * FROM t LIMIT 5", false},
+		{"SELECT 1 UNION ALL SELECT 2", "SELECT 1 UNION ALL SELECT 2", false},
+		{"INSERT INTO t SELECT 1", "INSERT INTO t SELECT 1", false},
+	}
+	for _, tt := range tests {
+		got, ok := appendLimit(tt.sql, 100)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("appendLimit(%q) = %q, %v; want %q, %v", tt.sql, got, ok, tt.want, tt.ok)
+		}
+	}
+}
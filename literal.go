@@ -0,0 +1,167 @@
+package clickhouse
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// formatComposite 若 v 是 Array、Map 或 Tuple 值，按 ClickHouse 字面量语法格式化并返回 true，
+// 如 [1,2,3]、{'a':1}、(1,'x')，结果可直接粘贴回 INSERT 语句。
+// typ 为列类型，用于区分 Tuple 与 Array、决定元素是否加引号；为空时按 Go 值的类型推断
+func formatComposite(v interface{}, typ string) (string, bool) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Map:
+	case reflect.Slice, reflect.Array:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			return "", false
+		}
+	default:
+		return "", false
+	}
+	var b strings.Builder
+	writeLiteral(&b, rv, typ)
+	return b.String(), true
+}
+
+// writeLiteral 递归输出值的 ClickHouse 字面量形式
+func writeLiteral(b *strings.Builder, rv reflect.Value, typ string) {
+	for rv.Kind() == reflect.Interface || rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			b.WriteString("NULL")
+			return
+		}
+		rv = rv.Elem()
+	}
+	if !rv.IsValid() {
+		b.WriteString("NULL")
+		return
+	}
+
+	typ = baseType(typ)
+	name, args := splitTypeArgs(typ)
+	switch rv.Kind() {
+	case reflect.Map:
+		if name == "Tuple" && rv.Type().Key().Kind() == reflect.String {
+			// 命名 Tuple 由驱动返回为 map[string]interface{}，按类型声明的字段顺序输出
+			b.WriteString("(")
+			for i, arg := range args {
+				if i > 0 {
+					b.WriteString(",")
+				}
+				field, fieldType := tupleField(arg)
+				writeLiteral(b, rv.MapIndex(reflect.ValueOf(field)), fieldType)
+			}
+			b.WriteString(")")
+			return
+		}
+		var keyType, valueType string
+		if name == "Map" && len(args) == 2 {
+			keyType, valueType = args[0], args[1]
+		}
+		keys := rv.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+		})
+		b.WriteString("{")
+		for i, k := range keys {
+			if i > 0 {
+				b.WriteString(",")
+			}
+			writeLiteral(b, k, keyType)
+			b.WriteString(":")
+			writeLiteral(b, rv.MapIndex(k), valueType)
+		}
+		b.WriteString("}")
+		return
+	case reflect.Slice, reflect.Array:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			break
+		}
+		left, right := "[", "]"
+		if name == "Tuple" {
+			left, right = "(", ")"
+		}
+		b.WriteString(left)
+		for i := 0; i < rv.Len(); i++ {
+			if i > 0 {
+				b.WriteString(",")
+			}
+			var elemType string
+			switch {
+			case name == "Array" && len(args) == 1:
+				elemType = args[0]
+			case name == "Tuple" && i < len(args):
+				_, elemType = tupleField(args[i])
+			}
+			writeLiteral(b, rv.Index(i), elemType)
+		}
+		b.WriteString(right)
+		return
+	}
+	b.WriteString(scalarLiteral(rv.Interface(), typ))
+}
+
+// scalarLiteral 输出标量元素：数值和布尔值原样输出，其余值（字符串、时间、UUID 等）加单引号
+func scalarLiteral(v interface{}, typ string) string {
+	switch val := v.(type) {
+	case time.Time:
+		return "'" + isoTime(val, typ) + "'"
+	case bool:
+		return formatValue(val)
+	}
+	switch reflect.ValueOf(v).Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return formatValue(v)
+	}
+	if isNumericType(typ) {
+		return formatValue(v)
+	}
+	return "'" + escapeString(formatValue(v)) + "'"
+}
+
+// splitTypeArgs 将 Name(arg1, arg2, ...) 拆成类型名和顶层参数，括号和引号内的逗号不拆分
+func splitTypeArgs(typ string) (string, []string) {
+	open := strings.IndexByte(typ, '(')
+	if open < 0 || !strings.HasSuffix(typ, ")") {
+		return typ, nil
+	}
+	var args []string
+	depth, start, quoted := 0, open+1, false
+	inner := typ[:len(typ)-1]
+	for i := open + 1; i < len(inner); i++ {
+		switch ch := inner[i]; {
+		case ch == '\\' && quoted:
+			i++
+		case ch == '\'':
+			quoted = !quoted
+		case quoted:
+		case ch == '(':
+			depth++
+		case ch == ')':
+			depth--
+		case ch == ',' && depth == 0:
+			args = append(args, strings.TrimSpace(inner[start:i]))
+			start = i + 1
+		}
+	}
+	args = append(args, strings.TrimSpace(inner[start:]))
+	return typ[:open], args
+}
+
+// tupleField 拆分 Tuple 元素声明：命名元素 "a Int64" 返回 ("a", "Int64")，未命名元素只返回类型
+func tupleField(arg string) (string, string) {
+	space := strings.IndexByte(arg, ' ')
+	if space < 0 {
+		return "", arg
+	}
+	if paren := strings.IndexByte(arg, '('); paren >= 0 && paren < space {
+		return "", arg
+	}
+	return strings.Trim(arg[:space], "`"), strings.TrimSpace(arg[space+1:])
+}
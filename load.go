@@ -0,0 +1,313 @@
+package clickhouse
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// loadBatchRows \load 每个批次提交的行数
+const loadBatchRows = 100000
+
+// loadFormats \load 支持的文件格式
+var loadFormats = []string{"CSV", "CSVWithNames", "TSV", "TSVWithNames", "TabSeparated", "TabSeparatedWithNames"}
+
+// loadPattern 解析 \load <file> INTO <table> FORMAT <format>，路径可以用单引号包围
+var loadPattern = regexp.MustCompile(`(?i)^(?:'([^']*)'|(\S+))\s+INTO\s+(\S+)\s+FORMAT\s+(\w+)$`)
+
+// loadFile 处理 \load <file> INTO <table> FORMAT <format>：按格式逐行解析本地文件，
+// 按列类型转换后通过批量 INSERT 分批写入；遇到不符合表结构的行时报告行号并停止
+func (c *CLI) loadFile(args string) {
+	m := loadPattern.FindStringSubmatch(strings.TrimSpace(args))
+	if m == nil {
+		fmt.Fprintf(c.term, "Usage: \\load <file> INTO <table> FORMAT CSV|CSVWithNames|TSV|TSVWithNames\n")
+		return
+	}
+	path, table := expandHome(m[1]+m[2]), m[3]
+	format, ok := lookupName(m[4], loadFormats)
+	if !ok {
+		fmt.Fprintf(c.term, "Unsupported format '%s'. Available: %s\n", m[4], strings.Join(loadFormats, ", "))
+		return
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		fmt.Fprintf(c.term, "Failed to open %s: %v\n\n", path, err)
+		return
+	}
+	defer f.Close()
+
+	columns, err := c.insertableColumns(table)
+	if err != nil {
+		c.printError(err)
+		return
+	}
+
+	startTime := time.Now()
+	var inserted int
+	interrupted := c.runInterruptible(func() {
+		inserted, err = c.loadRecords(newRecordReader(f, format), table, columns, strings.HasSuffix(format, "WithNames"))
+	})
+	switch {
+	case interrupted:
+		fmt.Fprintf(c.term, "Load cancelled, %d rows were inserted.\n\n", inserted)
+	case err != nil:
+		c.printError(err)
+		if inserted > 0 {
+			fmt.Fprintf(c.term, "%d rows were inserted before the error.\n\n", inserted)
+		}
+	default:
+		fmt.Fprintf(c.term, "Ok. %d rows inserted into %s. Elapsed: %.3f sec.\n\n",
+			inserted, table, time.Since(startTime).Seconds())
+	}
+}
+
+// loadRecords 读取所有记录并每 loadBatchRows 行提交一个批次，返回已提交的行数
+func (c *CLI) loadRecords(r *recordReader, table string, columns []columnInfo, withNames bool) (int, error) {
+	if withNames {
+		header, err := r.next()
+		if err == io.EOF {
+			return 0, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+		if columns, err = headerColumns(columns, header); err != nil {
+			return 0, fmt.Errorf("line %d: %v", r.line, err)
+		}
+	}
+
+	quoted := make([]string, len(columns))
+	for i, col := range columns {
+		quoted[i] = quoteIdentifier(col.name)
+	}
+	query := fmt.Sprintf("INSERT INTO %s (%s)", table, strings.Join(quoted, ", "))
+
+	inserted := 0
+	for {
+		n, err := c.loadBatch(r, query, columns)
+		inserted += n
+		if err != nil || n < loadBatchRows {
+			return inserted, err
+		}
+	}
+}
+
+// loadBatch 在一个批量 INSERT 中追加最多 loadBatchRows 行并提交，出错时整批回滚
+func (c *CLI) loadBatch(r *recordReader, query string, columns []columnInfo) (int, error) {
+	ctx, cancel := c.queryContext()
+	defer cancel()
+
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+	stmt, err := tx.PrepareContext(ctx, query)
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
+
+	n := 0
+	for n < loadBatchRows {
+		record, err := r.next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return 0, err
+		}
+		if len(record) != len(columns) {
+			return 0, fmt.Errorf("line %d: %d fields, expected %d", r.line, len(record), len(columns))
+		}
+
+		args := make([]interface{}, len(columns))
+		for i, col := range columns {
+			if args[i], err = parseLoadValue(record[i], col.typ); err != nil {
+				return 0, fmt.Errorf("line %d: column %s: %v", r.line, col.name, err)
+			}
+		}
+		if _, err := stmt.ExecContext(ctx, args...); err != nil {
+			return 0, fmt.Errorf("line %d: %v", r.line, err)
+		}
+		n++
+	}
+	if n == 0 {
+		return 0, nil
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+// insertableColumns 返回可以插入的表字段，跳过 MATERIALIZED 和 ALIAS 列
+func (c *CLI) insertableColumns(table string) ([]columnInfo, error) {
+	rs, err := c.runQuery("DESCRIBE TABLE " + table)
+	if err != nil {
+		return nil, err
+	}
+
+	cols := make([]columnInfo, 0, len(rs.rows))
+	for _, row := range rs.rows {
+		if len(row) > 2 {
+			if kind := formatValue(row[2]); kind == "MATERIALIZED" || kind == "ALIAS" {
+				continue
+			}
+		}
+		cols = append(cols, columnInfo{name: formatValue(row[0]), typ: formatValue(row[1])})
+	}
+	return cols, nil
+}
+
+// headerColumns 按文件表头的列名从表字段中选出插入的列
+func headerColumns(columns []columnInfo, header []string) ([]columnInfo, error) {
+	byName := make(map[string]columnInfo, len(columns))
+	for _, col := range columns {
+		byName[col.name] = col
+	}
+	selected := make([]columnInfo, len(header))
+	for i, name := range header {
+		col, ok := byName[strings.TrimSpace(name)]
+		if !ok {
+			return nil, fmt.Errorf("unknown column '%s' in header", name)
+		}
+		selected[i] = col
+	}
+	return selected, nil
+}
+
+// recordReader 逐条读取 CSV 或 TSV 记录，line 为最近一条记录的起始行号
+type recordReader struct {
+	csv  *csv.Reader
+	tsv  *bufio.Scanner
+	line int
+}
+
+// newRecordReader 按格式名创建记录读取器
+func newRecordReader(r io.Reader, format string) *recordReader {
+	if strings.HasPrefix(format, "CSV") {
+		cr := csv.NewReader(r)
+		cr.FieldsPerRecord = -1
+		cr.ReuseRecord = true
+		return &recordReader{csv: cr}
+	}
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	return &recordReader{tsv: sc}
+}
+
+// next 返回下一条记录，读完时返回 io.EOF；TSV 字段中的转义序列会被还原，\N 原样保留
+func (r *recordReader) next() ([]string, error) {
+	if r.csv != nil {
+		record, err := r.csv.Read()
+		if err != nil {
+			return nil, err
+		}
+		r.line, _ = r.csv.FieldPos(0)
+		return record, nil
+	}
+
+	if !r.tsv.Scan() {
+		if err := r.tsv.Err(); err != nil {
+			return nil, err
+		}
+		return nil, io.EOF
+	}
+	r.line++
+	fields := strings.Split(strings.TrimSuffix(r.tsv.Text(), "\r"), "\t")
+	for i, f := range fields {
+		if f != `\N` {
+			fields[i] = unescapeTSV(f)
+		}
+	}
+	return fields, nil
+}
+
+// parseLoadValue 将文本字段按列类型转换为驱动可以追加的值；\N 表示 NULL，非字符串列的空字段取默认值。
+// 数值、布尔和 Decimal 在客户端解析，字符串、日期时间、UUID、IP 和 Enum 由驱动解析
+func parseLoadValue(s, typ string) (interface{}, error) {
+	base := baseType(typ)
+	if s == `\N` {
+		if strings.Contains(typ, "Nullable(") {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("NULL in non-Nullable column of type %s", typ)
+	}
+
+	switch {
+	case base == "String", strings.HasPrefix(base, "FixedString"), strings.HasPrefix(base, "Enum"):
+		return s, nil
+	case s == "":
+		return nil, nil
+	case base == "UUID", base == "IPv4", base == "IPv6", strings.HasPrefix(base, "Date"):
+		return s, nil
+	case base == "Bool":
+		return strconv.ParseBool(s)
+	case strings.HasPrefix(base, "Decimal"):
+		return decimal.NewFromString(s)
+	case base == "Float32":
+		v, err := strconv.ParseFloat(s, 32)
+		return float32(v), err
+	case base == "Float64":
+		return strconv.ParseFloat(s, 64)
+	case strings.HasPrefix(base, "Int") || strings.HasPrefix(base, "UInt"):
+		return parseLoadInt(s, base)
+	}
+	return nil, fmt.Errorf("type %s is not supported by \\load, use \\paste instead", typ)
+}
+
+// parseLoadInt 按整数类型的位宽解析，返回与列类型一致的 Go 整数类型
+func parseLoadInt(s, typ string) (interface{}, error) {
+	unsigned := strings.HasPrefix(typ, "U")
+	bits, err := strconv.Atoi(strings.TrimPrefix(strings.TrimPrefix(typ, "U"), "Int"))
+	if err != nil {
+		return nil, fmt.Errorf("type %s is not supported by \\load", typ)
+	}
+	if bits > 64 {
+		v, ok := new(big.Int).SetString(s, 10)
+		if !ok {
+			return nil, fmt.Errorf("invalid integer %q", s)
+		}
+		return v, nil
+	}
+
+	if unsigned {
+		v, err := strconv.ParseUint(s, 10, bits)
+		if err != nil {
+			return nil, err
+		}
+		switch bits {
+		case 8:
+			return uint8(v), nil
+		case 16:
+			return uint16(v), nil
+		case 32:
+			return uint32(v), nil
+		}
+		return v, nil
+	}
+	v, err := strconv.ParseInt(s, 10, bits)
+	if err != nil {
+		return nil, err
+	}
+	switch bits {
+	case 8:
+		return int8(v), nil
+	case 16:
+		return int16(v), nil
+	case 32:
+		return int32(v), nil
+	}
+	return v, nil
+}
@@ -0,0 +1,102 @@
+package clickhouse
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+)
+
+// byteUnits 字节单位及其倍数，按从大到小排列
+var byteUnits = []struct {
+	suffix string
+	size   int64
+}{
+	{"T", 1 << 40}, {"G", 1 << 30}, {"M", 1 << 20}, {"K", 1 << 10},
+}
+
+// setMemWarn 设置查询峰值内存告警阈值，0 或 off 表示关闭
+func (c *CLI) setMemWarn(args string) {
+	switch strings.ToLower(args) {
+	case "":
+		if c.memWarn == 0 {
+			fmt.Fprintf(c.term, "Memory warning is off.\n")
+		} else {
+			fmt.Fprintf(c.term, "Memory warning threshold: %s\n", formatBytes(c.memWarn))
+		}
+		return
+	case "off", "0":
+		c.memWarn = 0
+		fmt.Fprintf(c.term, "Memory warning disabled.\n")
+		return
+	}
+
+	n, err := parseBytes(args)
+	if err != nil || n <= 0 {
+		fmt.Fprintf(c.term, "Invalid size '%s'. Use bytes or a suffix like 512M, 40G.\n", args)
+		return
+	}
+	c.memWarn = n
+	fmt.Fprintf(c.term, "Will warn when a query's peak memory exceeds %s.\n", formatBytes(n))
+}
+
+// withMemoryTracking 在开启告警时为查询注册 ProfileEvents 回调，
+// 记录服务端上报的 MemoryTrackerPeakUsage 最大值
+func (c *CLI) withMemoryTracking(ctx context.Context, peak *int64) context.Context {
+	if c.memWarn == 0 {
+		return ctx
+	}
+	return clickhouse.Context(ctx, clickhouse.WithProfileEvents(func(events []clickhouse.ProfileEvent) {
+		for _, e := range events {
+			if e.Name == "MemoryTrackerPeakUsage" && e.Value > *peak {
+				*peak = e.Value
+			}
+		}
+	}))
+}
+
+// checkMemWarn 峰值内存超过阈值时输出醒目告警
+func (c *CLI) checkMemWarn(peak int64) {
+	if c.memWarn == 0 || peak <= c.memWarn {
+		return
+	}
+	message := fmt.Sprintf("query used %s peak memory (threshold %s)", formatBytes(peak), formatBytes(c.memWarn))
+	if c.jsonOutput() {
+		c.writeEnvelope(&resultEnvelope{Status: "warning", QueryID: c.lastQueryID, Message: message})
+		return
+	}
+	fmt.Fprintf(c.term, "!!! WARNING: %s, query_id %s\n\n", message, c.lastQueryID)
+}
+
+// parseBytes 解析带可选 K/M/G/T 后缀（可带 B、iB）的字节数
+func parseBytes(s string) (int64, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	s = strings.TrimSuffix(strings.TrimSuffix(s, "B"), "I")
+
+	multiplier := int64(1)
+	for _, u := range byteUnits {
+		if strings.HasSuffix(s, u.suffix) {
+			multiplier = u.size
+			s = strings.TrimSpace(strings.TrimSuffix(s, u.suffix))
+			break
+		}
+	}
+
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, err
+	}
+	return int64(f * float64(multiplier)), nil
+}
+
+// formatBytes 将字节数格式化为可读形式，如 1.50 GiB
+func formatBytes(n int64) string {
+	for _, u := range byteUnits {
+		if n >= u.size {
+			return fmt.Sprintf("%.2f %siB", float64(n)/float64(u.size), u.suffix)
+		}
+	}
+	return fmt.Sprintf("%d B", n)
+}
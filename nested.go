@@ -0,0 +1,83 @@
+package clickhouse
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"unicode/utf8"
+)
+
+// nestedGroup 一个 Nested 列展开后的平行数组列，如 n.id、n.name
+type nestedGroup struct {
+	name   string
+	fields []int // 成员列下标
+}
+
+// nestedGroups 按 name.field 命名和 Array 类型识别 Nested 列，返回以首个成员列下标为键的分组；
+// 至少两个成员列共享前缀才视为 Nested
+func nestedGroups(rs *resultSet) map[int]*nestedGroup {
+	byName := make(map[string]*nestedGroup)
+	var order []*nestedGroup
+	for i, col := range rs.cols {
+		dot := strings.IndexByte(col, '.')
+		if dot <= 0 || dot == len(col)-1 || !strings.HasPrefix(baseType(rs.typeOf(i)), "Array(") {
+			continue
+		}
+		name := col[:dot]
+		g := byName[name]
+		if g == nil {
+			g = &nestedGroup{name: name}
+			byName[name] = g
+			order = append(order, g)
+		}
+		g.fields = append(g.fields, i)
+	}
+
+	groups := make(map[int]*nestedGroup)
+	for _, g := range order {
+		if len(g.fields) >= 2 {
+			groups[g.fields[0]] = g
+		}
+	}
+	return groups
+}
+
+// writeNested 将 Nested 列的平行数组按元素下标对齐，输出为缩进的子表，每个元素一行
+func (c *CLI) writeNested(w io.Writer, rs *resultSet, vals []interface{}, g *nestedGroup) {
+	cols := make([]string, len(g.fields))
+	widths := make([]int, len(g.fields))
+	arrays := make([]reflect.Value, len(g.fields))
+	n := 0
+	for j, i := range g.fields {
+		cols[j] = rs.cols[i][len(g.name)+1:]
+		widths[j] = utf8.RuneCountInString(cols[j])
+		arrays[j] = reflect.ValueOf(vals[i])
+		if arrays[j].Kind() == reflect.Slice || arrays[j].Kind() == reflect.Array {
+			n = max(n, arrays[j].Len())
+		}
+	}
+
+	rows := make([][]string, n)
+	for r := range rows {
+		rows[r] = make([]string, len(g.fields))
+		for j, i := range g.fields {
+			a := arrays[j]
+			if (a.Kind() != reflect.Slice && a.Kind() != reflect.Array) || r >= a.Len() {
+				continue
+			}
+			elemType := strings.TrimSuffix(strings.TrimPrefix(baseType(rs.typeOf(i)), "Array("), ")")
+			rows[r][j] = c.formatCell(a.Index(r).Interface(), elemType)
+			widths[j] = max(widths[j], utf8.RuneCountInString(rows[r][j]))
+		}
+	}
+
+	var b strings.Builder
+	compactStyle.writeHeader(&b, cols, nil, widths, nil)
+	for _, row := range rows {
+		compactStyle.writeRow(&b, row, widths, nil)
+	}
+	for _, line := range strings.Split(strings.TrimSuffix(b.String(), "\n"), "\n") {
+		fmt.Fprintf(w, "  %s\n", line)
+	}
+}
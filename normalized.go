@@ -0,0 +1,67 @@
+package clickhouse
+
+import (
+	"fmt"
+	"strings"
+)
+
+// setNormalized 处理 \normalized on|off
+func (c *CLI) setNormalized(args string) {
+	switch strings.ToLower(args) {
+	case "on":
+		c.normalized = true
+	case "off":
+		c.normalized = false
+	case "":
+		c.normalized = !c.normalized
+	default:
+		fmt.Fprintf(c.term, "Usage: \\normalized [on|off]\n")
+		return
+	}
+	if c.normalized {
+		fmt.Fprintf(c.term, "Server-side query form will be shown after each query.\n")
+	} else {
+		fmt.Fprintf(c.term, "Server-side query form is off.\n")
+	}
+}
+
+// showNormalized 从 system.query_log 读取查询在服务端的规范形式：
+// formatQuery 格式化后的语句、normalized_query_hash、实际访问的表和展开的视图
+func (c *CLI) showNormalized(queryID string) {
+	ctx, cancel := c.queryContext()
+	defer cancel()
+
+	// query_log 异步落盘，先刷新；没有 SYSTEM 权限时忽略错误
+	c.db.ExecContext(ctx, "SYSTEM FLUSH LOGS")
+
+	const query = `SELECT
+    %s,
+    toString(normalized_query_hash),
+    arrayStringConcat(tables, ', '),
+    arrayStringConcat(views, ', ')
+FROM system.query_log
+WHERE type = 'QueryFinish' AND event_date >= yesterday() AND query_id = ?
+LIMIT 1`
+
+	var formatted, hash, tables, views string
+	// formatQuery 需要 23.10 以上的服务端，旧版本退回 normalizeQuery（字面量替换为 ?）
+	err := c.db.QueryRowContext(ctx, fmt.Sprintf(query, "formatQuery(query)"), queryID).
+		Scan(&formatted, &hash, &tables, &views)
+	if err != nil && isServerException(err) {
+		err = c.db.QueryRowContext(ctx, fmt.Sprintf(query, "normalizeQuery(query)"), queryID).
+			Scan(&formatted, &hash, &tables, &views)
+	}
+	if err != nil {
+		fmt.Fprintf(c.term, "Server-side form not available: %v\n\n", err)
+		return
+	}
+
+	fmt.Fprintf(c.term, "Server-side query (normalized_query_hash %s):\n%s\n", hash, formatted)
+	if tables != "" {
+		fmt.Fprintf(c.term, "Tables: %s\n", tables)
+	}
+	if views != "" {
+		fmt.Fprintf(c.term, "Views:  %s\n", views)
+	}
+	fmt.Fprintf(c.term, "\n")
+}
@@ -0,0 +1,86 @@
+package clickhouse
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// defaultDigitSeparator 数字分组的默认分隔符
+const defaultDigitSeparator = ","
+
+// setPretty 处理 \pretty numbers [on|off] [separator]：表格和竖排输出中为数值列插入千位分隔符，
+// CSV/JSON 导出不受影响
+func (c *CLI) setPretty(args string) {
+	fields := strings.Fields(args)
+	if len(fields) == 0 || fields[0] != "numbers" || len(fields) > 3 {
+		fmt.Fprintf(c.term, "Usage: \\pretty numbers [on|off] [separator]\n")
+		return
+	}
+	fields = fields[1:]
+	if len(fields) == 0 {
+		c.groupDigits = !c.groupDigits
+	} else {
+		switch strings.ToLower(fields[0]) {
+		case "on":
+			c.groupDigits = true
+		case "off":
+			c.groupDigits = false
+		default:
+			fmt.Fprintf(c.term, "Usage: \\pretty numbers [on|off] [separator]\n")
+			return
+		}
+	}
+	if len(fields) == 2 {
+		sep := fields[1]
+		if len(sep) >= 2 && (sep[0] == '\'' || sep[0] == '"') && sep[len(sep)-1] == sep[0] {
+			sep = sep[1 : len(sep)-1]
+		}
+		c.digitSep = sep
+	}
+
+	if c.groupDigits {
+		fmt.Fprintf(c.term, "Numbers will be grouped with %q, e.g. %s.\n",
+			c.digitSep, groupDigits("1234567.89", c.digitSep))
+	} else {
+		fmt.Fprintf(c.term, "Digit grouping is off.\n")
+	}
+}
+
+// prettyNumber 开启 \pretty numbers 时为数值列的数值插入分组分隔符，其余值返回 false
+func (c *CLI) prettyNumber(v interface{}, typ string) (string, bool) {
+	if !c.groupDigits || !isNumericType(baseType(typ)) {
+		return "", false
+	}
+	switch reflect.ValueOf(v).Kind() {
+	case reflect.Map, reflect.Slice, reflect.Array, reflect.Ptr:
+		return "", false
+	}
+	return groupDigits(formatValue(v), c.digitSep), true
+}
+
+// groupDigits 每三位插入分隔符，只处理整数部分；inf、nan 和科学计数法原样返回
+func groupDigits(s, sep string) string {
+	sign := ""
+	if strings.HasPrefix(s, "-") {
+		sign, s = "-", s[1:]
+	}
+	intPart, frac := s, ""
+	if dot := strings.IndexByte(s, '.'); dot >= 0 {
+		intPart, frac = s[:dot], s[dot:]
+	}
+	if intPart == "" || strings.Trim(intPart, "0123456789") != "" || strings.ContainsAny(frac, "eE") {
+		return sign + s
+	}
+
+	var b strings.Builder
+	b.WriteString(sign)
+	for i, ch := range intPart {
+		if i > 0 && (len(intPart)-i)%3 == 0 {
+			b.WriteString(sep)
+		}
+		b.WriteRune(ch)
+	}
+	b.WriteString(frac)
+	return b.String()
+}
@@ -0,0 +1,117 @@
+package clickhouse
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// outfile 查询末尾 INTO OUTFILE '<path>' [APPEND|TRUNCATE] 子句
+type outfile struct {
+	path string
+	mode string // 空表示文件已存在时拒绝覆盖，APPEND 或 TRUNCATE
+}
+
+// splitOutfile 拆出语句顶层的 INTO OUTFILE 子句，其后的 FORMAT 子句保留在语句中
+func splitOutfile(sqlStr string) (string, *outfile, error) {
+	tokens := significantTokens(scanSQL(sqlStr))
+	for i, t := range tokens {
+		if t.depth != 0 || !t.isKeyword("INTO") || i+1 >= len(tokens) || !tokens[i+1].isKeyword("OUTFILE") {
+			continue
+		}
+		if i+2 >= len(tokens) || tokens[i+2].kind != tokenString {
+			return sqlStr, nil, fmt.Errorf("INTO OUTFILE expects a quoted file name")
+		}
+
+		out := &outfile{path: unquoteString(tokens[i+2].text)}
+		end := tokens[i+2].end
+		rest := tokens[i+3:]
+		if len(rest) > 0 && (rest[0].isKeyword("APPEND") || rest[0].isKeyword("TRUNCATE")) {
+			out.mode = strings.ToUpper(rest[0].text)
+			end = rest[0].end
+			rest = rest[1:]
+		}
+		if len(rest) > 0 && !rest[0].isKeyword("FORMAT") {
+			return sqlStr, nil, fmt.Errorf("unexpected '%s' after INTO OUTFILE; only APPEND, TRUNCATE and FORMAT may follow", rest[0].text)
+		}
+		return strings.TrimSpace(strings.TrimRight(sqlStr[:t.pos], " \t\r\n") + " " + strings.TrimLeft(sqlStr[end:], " \t\r\n")), out, nil
+	}
+	return sqlStr, nil, nil
+}
+
+// outfileFormat 未指定 FORMAT 时按扩展名推断输出格式，与 clickhouse-client 一样默认 TabSeparated
+func outfileFormat(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return "CSV"
+	case ".json":
+		return "JSON"
+	}
+	return "TabSeparated"
+}
+
+// format 返回写入文件使用的格式：FORMAT 子句指定的客户端格式，未指定时按扩展名推断；
+// 客户端无法生成的格式（如 Parquet）报错
+func (o *outfile) format(sqlStr, format string) (string, error) {
+	if format != "" {
+		return format, nil
+	}
+	tokens := significantTokens(scanSQL(sqlStr))
+	if n := len(tokens); n >= 2 && tokens[n-2].depth == 0 && tokens[n-2].isKeyword("FORMAT") {
+		return "", fmt.Errorf("format %s is not supported for INTO OUTFILE; use JSON, CSV, CSVWithNames, TSV or TSVWithNames", tokens[n-1].text)
+	}
+	return outfileFormat(o.path), nil
+}
+
+// writeOutfile 将结果按格式写入 INTO OUTFILE 指定的文件，完成后输出行数和字节数
+func (c *CLI) writeOutfile(rows *sql.Rows, out *outfile, format string) {
+	f, err := out.open()
+	if err != nil {
+		c.printError(err)
+		return
+	}
+	cw := &countingWriter{w: f}
+	count, err := c.writeFormatted(cw, rows, format)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	c.lastRowCount = count
+	if err != nil {
+		c.printError(err)
+		return
+	}
+	fmt.Fprintf(c.term, "Ok. %d rows (%d bytes, %s) written to %s.\n\n", count, cw.n, format, out.path)
+}
+
+// open 按 APPEND/TRUNCATE 修饰打开输出文件，未指定时文件已存在则报错
+func (o *outfile) open() (*os.File, error) {
+	flags := os.O_WRONLY | os.O_CREATE
+	switch o.mode {
+	case "APPEND":
+		flags |= os.O_APPEND
+	case "TRUNCATE":
+		flags |= os.O_TRUNC
+	default:
+		flags |= os.O_EXCL
+	}
+	f, err := os.OpenFile(expandHome(o.path), flags, 0o644)
+	if os.IsExist(err) {
+		return nil, fmt.Errorf("file %s already exists, add APPEND or TRUNCATE after the file name", o.path)
+	}
+	return f, err
+}
+
+// countingWriter 统计写入的字节数
+type countingWriter struct {
+	w *os.File
+	n int64
+}
+
+// Write 写入底层文件并累加字节数
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	return n, err
+}
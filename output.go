@@ -0,0 +1,127 @@
+package clickhouse
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// 交互输出模式
+const (
+	outputText = "text"
+	outputJSON = "json"
+)
+
+// resultEnvelope JSON 输出模式下每次交互输出的结果对象
+type resultEnvelope struct {
+	Status       string          `json:"status"`
+	Meta         []columnMeta    `json:"meta,omitempty"`
+	Rows         [][]interface{} `json:"rows,omitempty"`
+	RowCount     *int            `json:"row_count,omitempty"`
+	AffectedRows *int64          `json:"affected_rows,omitempty"`
+	QueryID      string          `json:"query_id,omitempty"`
+	ElapsedMs    *int64          `json:"elapsed_ms,omitempty"`
+	Code         *int32          `json:"code,omitempty"`
+	Message      string          `json:"message,omitempty"`
+}
+
+// columnMeta 结果列的名称和类型
+type columnMeta struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// setOutput 切换交互输出模式：text 为默认的表格输出，json 为每次交互一行 JSON
+func (c *CLI) setOutput(args string) {
+	switch strings.ToLower(args) {
+	case "":
+		fmt.Fprintf(c.term, "Output mode: %s. Available: text, json\n", c.output)
+	case outputText:
+		c.output = outputText
+		fmt.Fprintf(c.term, "Output mode set to text.\n")
+	case outputJSON, "json-compact":
+		c.output = outputJSON
+		c.writeEnvelope(&resultEnvelope{Status: "ok", Message: "output mode set to json"})
+	default:
+		fmt.Fprintf(c.term, "Unknown output mode '%s'. Available: text, json\n", args)
+	}
+}
+
+// jsonOutput 是否处于 JSON 信封输出模式
+func (c *CLI) jsonOutput() bool {
+	return c.output == outputJSON
+}
+
+// writeResultEnvelope 以 JSON 信封输出查询结果
+func (c *CLI) writeResultEnvelope(rs *resultSet, startTime time.Time) {
+	env := &resultEnvelope{Status: "ok", QueryID: c.lastQueryID, Rows: [][]interface{}{}}
+	for i, col := range rs.cols {
+		env.Meta = append(env.Meta, columnMeta{Name: col, Type: rs.typeOf(i)})
+	}
+	for _, row := range rs.rows {
+		vals := make([]interface{}, len(row))
+		for i, v := range row {
+			vals[i] = c.jsonValue(v, rs.typeOf(i))
+		}
+		env.Rows = append(env.Rows, vals)
+	}
+	count := len(rs.rows)
+	env.RowCount = &count
+	env.ElapsedMs = elapsedMs(startTime)
+	c.writeEnvelope(env)
+}
+
+// writeAffectedEnvelope 以 JSON 信封输出非查询语句的执行结果
+func (c *CLI) writeAffectedEnvelope(affected int64, startTime time.Time) {
+	c.writeEnvelope(&resultEnvelope{
+		Status:       "ok",
+		QueryID:      c.lastQueryID,
+		AffectedRows: &affected,
+		ElapsedMs:    elapsedMs(startTime),
+	})
+}
+
+// writeErrorEnvelope 以 JSON 信封输出错误，服务端异常带上错误码
+func (c *CLI) writeErrorEnvelope(err error) {
+	env := &resultEnvelope{Status: "error", Message: err.Error()}
+	if exception, ok := serverException(err); ok {
+		env.Code = &exception.Code
+		env.Message = exception.Message
+	}
+	c.writeEnvelope(env)
+}
+
+// writeEnvelope 输出一行 JSON；值无法编码时退回为字符串
+func (c *CLI) writeEnvelope(env *resultEnvelope) {
+	data, err := json.Marshal(env)
+	if err != nil {
+		for _, row := range env.Rows {
+			for i, v := range row {
+				row[i] = formatValue(v)
+			}
+		}
+		data, err = json.Marshal(env)
+		if err != nil {
+			data, _ = json.Marshal(&resultEnvelope{Status: "error", Message: err.Error()})
+		}
+	}
+	fmt.Fprintf(c.term, "%s\n", data)
+}
+
+// jsonValue 将单元格转换为适合 JSON 编码的值：时间按 \datetime 设置格式化，[]byte 转为字符串
+func (c *CLI) jsonValue(v interface{}, typ string) interface{} {
+	switch val := v.(type) {
+	case time.Time:
+		return c.formatCell(val, typ)
+	case []byte:
+		return string(val)
+	}
+	return v
+}
+
+// elapsedMs 返回从 startTime 起经过的毫秒数
+func elapsedMs(startTime time.Time) *int64 {
+	ms := time.Since(startTime).Milliseconds()
+	return &ms
+}
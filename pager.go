@@ -0,0 +1,254 @@
+package clickhouse
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/chzyer/readline"
+)
+
+// pagerSampleRows 流式分页或流式输出时用于确定列宽的前几行
+const pagerSampleRows = 200
+
+// pagerFlushRows 流式写入分页器时每多少行刷新一次缓冲
+const pagerFlushRows = 100
+
+// setPager 处理 \pager on|off|auto|<command>，on/auto 时使用 $PAGER，未设置则用 less -SR
+// auto 只在结果超过一屏时才使用分页器
+func (c *CLI) setPager(args string) {
+	auto := false
+	switch strings.ToLower(args) {
+	case "":
+		switch {
+		case c.pager == "":
+			fmt.Fprintf(c.term, "Pager is off.\n")
+		case c.pagerAuto:
+			fmt.Fprintf(c.term, "Pager: %s (results taller than the screen)\n", c.pager)
+		default:
+			fmt.Fprintf(c.term, "Pager: %s\n", c.pager)
+		}
+		return
+	case "off":
+		c.pager = ""
+		c.pagerAuto = false
+		fmt.Fprintf(c.term, "Pager disabled.\n")
+		return
+	case "on", "auto":
+		auto = strings.EqualFold(args, "auto")
+		args = os.Getenv("PAGER")
+		if args == "" {
+			args = "less -SR"
+		}
+	}
+
+	if !c.isLocalTerminal() {
+		fmt.Fprintf(c.term, "Pager is only available when running on a local terminal.\n")
+		return
+	}
+	c.pager = args
+	c.pagerAuto = auto
+	if auto {
+		fmt.Fprintf(c.term, "Pager set to %s for results taller than the screen.\n", c.pager)
+	} else {
+		fmt.Fprintf(c.term, "Pager set to %s.\n", c.pager)
+	}
+}
+
+// isLocalTerminal 判断 CLI 是否直接运行在本地终端上（而不是 SSH 会话等自定义 Terminal）
+func (c *CLI) isLocalTerminal() bool {
+	f, ok := c.term.(*os.File)
+	return ok && readline.IsTerminal(int(f.Fd()))
+}
+
+// usePager 当前查询结果是否边读取边流式写入分页器（\pager on 且为普通表格输出，
+// EXPLAIN、EXISTS、DESCRIBE 和 \collapse 仍先完整渲染再交给分页器）
+func (c *CLI) usePager(sqlStr string) bool {
+	return c.pager != "" && !c.pagerAuto && c.grep == nil && !c.jsonOutput() && !c.verticalMode &&
+		!c.transposeMode && c.streamableFormat() && c.streamableStatement(sqlStr) && c.pagerReady()
+}
+
+// pagerReady 判断分页器是否可用：输出的终端必须是本地终端（没有重定向到文件，也不是 SSH 会话等自定义 Terminal），
+// 且分页器命令存在。命令不存在时提示一次并关闭分页器，之后直接输出
+func (c *CLI) pagerReady() bool {
+	if c.pager == "" || c.jsonOutput() || !c.isLocalTerminal() {
+		return false
+	}
+	name := strings.Fields(c.pager)[0]
+	if _, err := exec.LookPath(name); err != nil {
+		fmt.Fprintf(c.term, "Pager '%s' not found, printing results directly (\\pager off to silence).\n", name)
+		c.pager = ""
+		c.pagerAuto = false
+		return false
+	}
+	return true
+}
+
+// pageOutput 将已渲染好的结果交给分页器；auto 模式下不超过一屏时直接输出，分页器无法启动时也直接输出
+func (c *CLI) pageOutput(data []byte) {
+	if c.pagerAuto && bytes.Count(data, []byte("\n")) < c.termHeight() {
+		c.term.Write(data)
+		return
+	}
+
+	cmd := c.pagerCommand()
+	cmd.Stdin = bytes.NewReader(data)
+	cmd.Stdout = c.term
+	cmd.Stderr = os.Stderr
+	if err := cmd.Start(); err != nil {
+		fmt.Fprintf(c.term, "Failed to start pager '%s': %v\n", c.pager, err)
+		c.term.Write(data)
+		return
+	}
+	cmd.Wait()
+}
+
+// pagerCommand 创建分页器进程；表格带颜色时在 LESS 中追加 -R，让 less 显示颜色而不是转义序列
+func (c *CLI) pagerCommand() *exec.Cmd {
+	cmd := exec.Command("sh", "-c", c.pager)
+	if c.useColor() {
+		cmd.Env = append(os.Environ(), "LESS="+strings.TrimSpace(os.Getenv("LESS")+" -R"))
+	}
+	return cmd
+}
+
+// termHeight 返回终端高度：优先 $LINES，其次配置的本地终端，默认 24
+func (c *CLI) termHeight() int {
+	if n, err := strconv.Atoi(os.Getenv("LINES")); err == nil && n > 0 {
+		return n
+	}
+	if f, ok := c.term.(*os.File); ok {
+		if _, h, err := readline.GetSize(int(f.Fd())); err == nil && h > 0 {
+			return h
+		}
+	}
+	return 24
+}
+
+// pageRows 将查询结果边读取边写入分页器，不受 maxRows 限制
+// 列宽由前 pagerSampleRows 行确定，之后的行按该宽度截断；分页器提前退出时取消查询
+func (c *CLI) pageRows(rows *sql.Rows, cancel context.CancelFunc, sqlStr string, startTime time.Time) {
+	cmd := c.pagerCommand()
+	cmd.Stdout = c.term
+	cmd.Stderr = os.Stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		c.printError(err)
+		return
+	}
+	if err := cmd.Start(); err != nil {
+		fmt.Fprintf(c.term, "Failed to start pager '%s': %v\n\n", c.pager, err)
+		return
+	}
+
+	// 分页器退出（包括用户按 q）后立即取消查询，不必等到下一次写入失败
+	pagerDone := make(chan struct{})
+	go func() {
+		cmd.Wait()
+		cancel()
+		close(pagerDone)
+	}()
+
+	count, quit, err := c.streamRows(bufio.NewWriter(stdin), rows, 0)
+	stdin.Close()
+	<-pagerDone
+
+	switch {
+	case quit:
+		fmt.Fprintf(c.term, "Pager closed, query cancelled after %d rows.\n\n", count)
+	case err != nil:
+		c.printError(err)
+	default:
+		c.printFooter(c.term, count, startTime)
+	}
+
+	c.lastRowCount = count
+	c.lastResult = nil
+	c.trackPage(sqlStr)
+}
+
+// streamRows 将结果写入 w，最多 limit 行（0 表示不限制），返回写出的行数；
+// 写入失败说明分页器已退出，查询被取消时同样返回 quit 为 true
+func (c *CLI) streamRows(w *bufio.Writer, rows *sql.Rows, limit int) (count int, quit bool, err error) {
+	cols, err := rows.Columns()
+	if err != nil {
+		return 0, false, err
+	}
+	rs := &resultSet{cols: cols, types: make([]string, len(cols))}
+	if colTypes, err := rows.ColumnTypes(); err == nil {
+		for i, ct := range colTypes {
+			rs.types[i] = ct.DatabaseTypeName()
+		}
+	}
+
+	for len(rs.rows) < pagerSampleRows && (limit == 0 || len(rs.rows) < limit) && rows.Next() {
+		vals, err := scanRow(rows, len(cols))
+		if err != nil {
+			return 0, false, err
+		}
+		rs.rows = append(rs.rows, vals)
+	}
+
+	cells, colWidths := c.tableCells(rs)
+	st := c.tableStyle()
+	right := c.columnAlign(rs.cols, rs.types)
+	st.writeHeader(w, rs.cols, c.headerTypes(rs.types), colWidths, right)
+	for _, row := range cells {
+		st.writeRow(w, row, colWidths, right)
+	}
+	count = len(cells)
+	if err := w.Flush(); err != nil {
+		return count, true, nil
+	}
+
+	for len(rs.rows) == pagerSampleRows && (limit == 0 || count < limit) && rows.Next() {
+		vals, err := scanRow(rows, len(cols))
+		if err != nil {
+			return count, false, err
+		}
+		row := make([]string, len(vals))
+		for i, v := range vals {
+			row[i] = st.fit(c.formatCell(v, rs.typeOf(i)), colWidths[i])
+		}
+		st.writeRow(w, row, colWidths, right)
+		count++
+
+		if count%pagerFlushRows == 0 {
+			if err := w.Flush(); err != nil {
+				return count, true, nil
+			}
+		}
+	}
+	st.writeRule(w, st.bottom, colWidths)
+	if err := w.Flush(); err != nil {
+		return count, true, nil
+	}
+
+	if err := rows.Err(); err != nil {
+		if errors.Is(err, context.Canceled) {
+			return count, true, nil
+		}
+		return count, false, err
+	}
+	return count, false, nil
+}
+
+// fitCell 将超出列宽（字符数）的值截断并以 ... 结尾，在字符边界处截断
+func fitCell(s string, width int) string {
+	if utf8.RuneCountInString(s) <= width {
+		return s
+	}
+	if width <= 3 {
+		return s[:runeOffset(s, width)]
+	}
+	return s[:runeOffset(s, width-3)] + "..."
+}
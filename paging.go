@@ -0,0 +1,125 @@
+package clickhouse
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// pageState 服务端分页状态（基于 LIMIT/OFFSET）
+type pageState struct {
+	query  string // 去掉 LIMIT/OFFSET 后的查询
+	limit  int
+	offset int
+}
+
+// sql 生成当前页的查询语句
+func (p *pageState) sql() string {
+	return fmt.Sprintf("%s LIMIT %d OFFSET %d", p.query, p.limit, p.offset)
+}
+
+// parsePageQuery 解析以 LIMIT n [OFFSET m] 或 LIMIT m, n 结尾的 SELECT 语句
+// 只接受没有副作用的 SELECT/WITH 查询，否则返回 nil
+func parsePageQuery(sqlStr string) *pageState {
+	if kw := firstKeyword(sqlStr); kw != "SELECT" && kw != "WITH" {
+		return nil
+	}
+
+	tokens := significantTokens(scanSQL(sqlStr))
+	limitIdx := -1
+	for i := len(tokens) - 1; i >= 0; i-- {
+		if tokens[i].depth == 0 && tokens[i].isKeyword("LIMIT") {
+			limitIdx = i
+			break
+		}
+	}
+	if limitIdx <= 0 {
+		return nil
+	}
+
+	var nums []int
+	hasOffset, hasComma := false, false
+	for _, t := range tokens[limitIdx+1:] {
+		switch {
+		case t.kind == tokenNumber:
+			n, err := strconv.Atoi(t.text)
+			if err != nil {
+				return nil
+			}
+			nums = append(nums, n)
+		case t.isKeyword("OFFSET") && len(nums) == 1 && !hasOffset && !hasComma:
+			hasOffset = true
+		case t.text == "," && len(nums) == 1 && !hasOffset && !hasComma:
+			hasComma = true
+		default:
+			// LIMIT n BY ... / WITH TIES 等无法安全分页
+			return nil
+		}
+	}
+
+	page := &pageState{query: strings.TrimSpace(sqlStr[:tokens[limitIdx].pos])}
+	switch {
+	case len(nums) == 1 && !hasOffset && !hasComma:
+		page.limit = nums[0]
+	case len(nums) == 2 && hasOffset:
+		page.limit, page.offset = nums[0], nums[1]
+	case len(nums) == 2 && hasComma:
+		page.offset, page.limit = nums[0], nums[1]
+	default:
+		return nil
+	}
+	if page.limit <= 0 {
+		return nil
+	}
+	return page
+}
+
+// trackPage 记录可分页查询，供 \next / \prev 使用；\limit 自动追加的 LIMIT 不算用户分页
+func (c *CLI) trackPage(sqlStr string) {
+	page := parsePageQuery(sqlStr)
+	if page == nil || c.limitAdded {
+		c.page = nil
+		return
+	}
+
+	// 首次遇到该查询时提示缺少 ORDER BY
+	if (c.page == nil || c.page.query != page.query) && !hasTopLevelKeywords(page.query, "ORDER", "BY") {
+		fmt.Fprintf(c.term, "Warning: query has no ORDER BY, pages may overlap or skip rows.\n\n")
+	}
+	c.page = page
+}
+
+// nextPage 向后翻页
+func (c *CLI) nextPage() {
+	if c.page == nil {
+		fmt.Fprintf(c.term, "No pageable query. Run a SELECT ... LIMIT n first.\n")
+		return
+	}
+
+	prev := *c.page
+	c.page.offset += c.page.limit
+	c.executeSQL(c.page.sql())
+	if c.lastRowCount == 0 && c.page != nil {
+		// 已经到末尾，退回上一页位置
+		*c.page = prev
+		fmt.Fprintf(c.term, "No more rows.\n")
+	}
+}
+
+// prevPage 向前翻页
+func (c *CLI) prevPage() {
+	if c.page == nil {
+		fmt.Fprintf(c.term, "No pageable query. Run a SELECT ... LIMIT n first.\n")
+		return
+	}
+	if c.page.offset == 0 {
+		fmt.Fprintf(c.term, "Already at the first page.\n")
+		return
+	}
+
+	c.page.offset -= c.page.limit
+	if c.page.offset < 0 {
+		c.page.offset = 0
+	}
+	c.executeSQL(c.page.sql())
+}
@@ -0,0 +1,67 @@
+package clickhouse
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ExecParams 执行单条语句，args 原样作为绑定参数交给驱动的 QueryContext/ExecContext，
+// 避免调用方自行拼接 SQL：? 和 $1 按位置绑定，clickhouse.Named 绑定 @name；
+// 语句中含 {name:Type} 时，clickhouse.Named 的字符串值作为服务端查询参数发送。
+// 结果按当前输出设置打印，语句失败时返回其错误；尚未连接时静默连接
+func (c *CLI) ExecParams(ctx context.Context, sqlStr string, args ...interface{}) error {
+	if c.db == nil {
+		if err := c.connect(false); err != nil {
+			return err
+		}
+	}
+
+	prevCtx, prevArgs := c.stmtCtx, c.stmtArgs
+	c.stmtCtx, c.stmtArgs = ctx, args
+	defer func() { c.stmtCtx, c.stmtArgs = prevCtx, prevArgs }()
+
+	c.lastErr = nil
+	c.executeSQL(strings.TrimSuffix(strings.TrimSpace(sqlStr), ";"))
+	if c.lastErr != nil {
+		return c.lastErr
+	}
+	return ctx.Err()
+}
+
+// setParam 处理 \param [name value | reset]：设置交互语句中 {name:Type} 占位符的值，
+// 值以服务端查询参数发送，由服务端按占位符的类型解析，不会拼接进 SQL
+func (c *CLI) setParam(args string) {
+	name, value, _ := strings.Cut(args, " ")
+	name, value = strings.TrimSpace(name), strings.TrimSpace(value)
+	switch {
+	case name == "":
+		if len(c.queryParams) == 0 {
+			fmt.Fprintf(c.term, "No query parameters set.\n")
+			return
+		}
+		names := make([]string, 0, len(c.queryParams))
+		for n := range c.queryParams {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		for _, n := range names {
+			fmt.Fprintf(c.term, "%s = %q\n", n, c.queryParams[n])
+		}
+		return
+	case strings.EqualFold(name, "reset") && value == "":
+		c.queryParams = nil
+		fmt.Fprintf(c.term, "Query parameters cleared.\n")
+		return
+	case value == "":
+		fmt.Fprintf(c.term, "Usage: \\param <name> <value> | reset\n")
+		return
+	}
+
+	if c.queryParams == nil {
+		c.queryParams = make(map[string]string)
+	}
+	c.queryParams[name] = unquoteString(value)
+	fmt.Fprintf(c.term, "Parameter %s set; use it as {%s:Type}.\n", name, name)
+}
@@ -0,0 +1,89 @@
+package clickhouse
+
+import (
+	"fmt"
+	"strings"
+)
+
+// partsPerPartitionWarn 单个分区活跃 part 数超过该值时提示碎片化
+// （与服务端 parts_to_delay_insert 的默认值一致，超过后写入开始被延迟）
+const partsPerPartitionWarn = 150
+
+// showParts 显示 MergeTree 表的活跃 part，按修改时间排序，并汇总数量和大小
+func (c *CLI) showParts(table string) {
+	if table == "" {
+		fmt.Fprintf(c.term, "Usage: \\parts <table>\n")
+		return
+	}
+	database, name := splitQualifiedName(table, c.database)
+
+	var (
+		parts, partitions, maxPerPartition uint64
+		rows                               uint64
+		compressed, uncompressed           string
+	)
+	ctx, cancel := c.queryContext()
+	defer cancel()
+	err := c.db.QueryRowContext(ctx, `SELECT
+    sum(parts),
+    count(),
+    max(parts),
+    sum(rows),
+    formatReadableSize(sum(compressed)),
+    formatReadableSize(sum(uncompressed))
+FROM (
+    SELECT
+        count() AS parts,
+        sum(rows) AS rows,
+        sum(data_compressed_bytes) AS compressed,
+        sum(data_uncompressed_bytes) AS uncompressed
+    FROM system.parts
+    WHERE active AND database = ? AND table = ?
+    GROUP BY partition
+)`, database, name).
+		Scan(&parts, &partitions, &maxPerPartition, &rows, &compressed, &uncompressed)
+	if err != nil {
+		c.printError(err)
+		return
+	}
+	if parts == 0 {
+		fmt.Fprintf(c.term, "No active parts for %s.%s (not a MergeTree table, empty, or does not exist).\n\n", database, name)
+		return
+	}
+
+	rs, err := c.runQuery(`SELECT
+    partition,
+    name,
+    rows,
+    formatReadableSize(data_compressed_bytes) AS compressed,
+    formatReadableSize(data_uncompressed_bytes) AS uncompressed,
+    level,
+    modification_time
+FROM system.parts
+WHERE active AND database = ? AND table = ?
+ORDER BY modification_time`, database, name)
+	if err != nil {
+		c.printError(err)
+		return
+	}
+
+	c.displayTable(c.term, rs)
+	if uint64(len(rs.rows)) < parts {
+		fmt.Fprintf(c.term, "Showing the oldest %d of %d parts.\n", len(rs.rows), parts)
+	}
+	fmt.Fprintf(c.term, "%d active parts in %d partitions, %d rows, %s compressed, %s uncompressed.\n",
+		parts, partitions, rows, compressed, uncompressed)
+	if maxPerPartition > partsPerPartitionWarn {
+		fmt.Fprintf(c.term, "Warning: a partition has %d parts; the table is fragmented. Consider OPTIMIZE TABLE %s or fewer, larger inserts.\n",
+			maxPerPartition, table)
+	}
+	fmt.Fprintf(c.term, "\n")
+}
+
+// splitQualifiedName 拆分 db.table，未指定库时使用 defaultDB
+func splitQualifiedName(name, defaultDB string) (string, string) {
+	if i := strings.IndexByte(name, '.'); i >= 0 {
+		return strings.Trim(name[:i], "`\""), strings.Trim(name[i+1:], "`\"")
+	}
+	return defaultDB, strings.Trim(name, "`\"")
+}
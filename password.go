@@ -0,0 +1,45 @@
+package clickhouse
+
+import (
+	"fmt"
+	"os"
+
+	"golang.org/x/term"
+)
+
+// passwordEnv 未配置密码时读取的环境变量，与 clickhouse-client 一致
+const passwordEnv = "CLICKHOUSE_PASSWORD"
+
+// authFailedCode 服务端错误码 AUTHENTICATION_FAILED
+const authFailedCode = 516
+
+// canPrompt 能否通过配置的终端向用户提问：本地文件只有是终端时才可以，
+// 自定义 Terminal（如 SSH 会话）视为交互式
+func (c *CLI) canPrompt() bool {
+	if f, ok := c.term.(*os.File); ok {
+		return term.IsTerminal(int(f.Fd()))
+	}
+	return true
+}
+
+// askPassword 通过配置的终端不回显地询问密码，直接回车表示空密码。
+// 本地终端用 golang.org/x/term 关闭回显，自定义 Terminal 由 readline 处理
+func (c *CLI) askPassword() error {
+	prompt := fmt.Sprintf("Password for %s@%s: ", c.username, c.addr())
+	if f, ok := c.term.(*os.File); ok {
+		fmt.Fprint(c.term, prompt)
+		b, err := term.ReadPassword(int(f.Fd()))
+		fmt.Fprintf(c.term, "\n")
+		if err != nil {
+			return err
+		}
+		c.password = string(b)
+		return nil
+	}
+	password, err := c.reader.ReadPassword(prompt)
+	if err != nil {
+		return err
+	}
+	c.password = password
+	return nil
+}
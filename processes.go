@@ -0,0 +1,146 @@
+package clickhouse
+
+import (
+	"fmt"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/google/uuid"
+)
+
+// killMyQueries 终止当前用户所有正在运行的查询（不包括本次 KILL 语句本身）
+func (c *CLI) killMyQueries() {
+	ctx, cancel := c.queryContext()
+	defer cancel()
+
+	countID := uuid.NewString()
+	var running uint64
+	err := c.db.QueryRowContext(clickhouse.Context(ctx, clickhouse.WithQueryID(countID)),
+		"SELECT count() FROM system.processes WHERE user = currentUser() AND query_id != ?", countID).Scan(&running)
+	if err != nil {
+		c.printError(err)
+		return
+	}
+	if running == 0 {
+		fmt.Fprintf(c.term, "No running queries to kill.\n\n")
+		return
+	}
+
+	if !c.confirm(fmt.Sprintf("Kill %d running queries owned by %s?", running, c.username)) {
+		fmt.Fprintf(c.term, "Cancelled.\n\n")
+		return
+	}
+
+	killID := uuid.NewString()
+	rows, err := c.db.QueryContext(clickhouse.Context(ctx, clickhouse.WithQueryID(killID)),
+		"KILL QUERY WHERE user = currentUser() AND query_id != ? ASYNC", killID)
+	if err != nil {
+		c.printError(err)
+		return
+	}
+	defer rows.Close()
+
+	killed := 0
+	for rows.Next() {
+		killed++
+	}
+	if err := rows.Err(); err != nil {
+		c.printError(err)
+		return
+	}
+
+	fmt.Fprintf(c.term, "Killed %d queries.\n\n", killed)
+}
+
+// killQuery 处理 \kill [query_id]：通过 KILL QUERY 终止服务端仍在运行的查询，
+// 省略 query_id 时终止本会话最近执行的语句，适用于客户端已经放弃但服务端仍在执行的查询
+func (c *CLI) killQuery(queryID string) {
+	queryID = unquoteString(queryID)
+	if queryID == "" {
+		queryID = c.lastQueryID
+	}
+	if queryID == "" {
+		fmt.Fprintf(c.term, "Usage: \\kill [query_id] (no query has been run in this session yet)\n")
+		return
+	}
+
+	ctx, cancel := c.queryContext()
+	defer cancel()
+
+	rows, err := c.db.QueryContext(ctx, "KILL QUERY WHERE query_id = ? ASYNC", queryID)
+	if err != nil {
+		c.printError(err)
+		return
+	}
+	defer rows.Close()
+
+	cols, err := rows.Columns()
+	if err != nil {
+		c.printError(err)
+		return
+	}
+	// 第一列为 kill_status：ASYNC 时为 waiting，查询已结束时不返回行
+	status := ""
+	for rows.Next() {
+		vals, err := scanRow(rows, len(cols))
+		if err != nil {
+			c.printError(err)
+			return
+		}
+		status = formatValue(vals[0])
+	}
+	if err := rows.Err(); err != nil {
+		c.printError(err)
+		return
+	}
+
+	if status == "" {
+		fmt.Fprintf(c.term, "Query %s is not running.\n\n", queryID)
+		return
+	}
+	fmt.Fprintf(c.term, "Kill requested for query %s (%s).\n\n", queryID, status)
+}
+
+// peekQuery 以纵向方式显示 system.processes 中某个正在运行查询的详情
+func (c *CLI) peekQuery(queryID string) {
+	if queryID == "" {
+		fmt.Fprintf(c.term, "Usage: \\peek <query_id>\n")
+		return
+	}
+
+	rs, err := c.runQuery(`SELECT
+    query_id,
+    user,
+    address,
+    round(elapsed, 3) AS elapsed_sec,
+    read_rows,
+    total_rows_approx,
+    if(total_rows_approx > 0, concat(toString(round(read_rows * 100 / total_rows_approx, 1)), '%'), '') AS progress,
+    formatReadableSize(read_bytes) AS read,
+    formatReadableSize(memory_usage) AS memory,
+    formatReadableSize(peak_memory_usage) AS peak_memory,
+    query
+FROM system.processes
+WHERE query_id = ?`, queryID)
+	if err != nil {
+		c.printError(err)
+		return
+	}
+
+	if len(rs.rows) == 0 {
+		ctx, cancel := c.queryContext()
+		defer cancel()
+
+		var finishType string
+		err := c.db.QueryRowContext(ctx, `SELECT toString(type) FROM system.query_log
+WHERE query_id = ? AND event_date >= yesterday() AND type != 'QueryStart'
+ORDER BY event_time DESC LIMIT 1`, queryID).Scan(&finishType)
+		if err == nil {
+			fmt.Fprintf(c.term, "Query %s is no longer running (%s).\n\n", queryID, finishType)
+		} else {
+			fmt.Fprintf(c.term, "Query %s is not running.\n\n", queryID)
+		}
+		return
+	}
+
+	c.displayVertical(c.term, rs)
+}
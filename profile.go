@@ -0,0 +1,144 @@
+package clickhouse
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// keyProfileEvents 衡量查询工作量最常用的计数器，对比时优先显示
+var keyProfileEvents = []string{
+	"SelectedParts", "SelectedMarks", "SelectedRows", "SelectedBytes",
+	"ReadCompressedBytes", "OSCPUVirtualTimeMicroseconds", "RealTimeMicroseconds",
+}
+
+// compareEvents 对比两个查询在 system.query_log 中记录的 ProfileEvents
+func (c *CLI) compareEvents(args string) {
+	ids := strings.Fields(args)
+	switch len(ids) {
+	case 0:
+		if c.prevQueryID == "" {
+			fmt.Fprintf(c.term, "Need two executed queries to compare. Run two queries or pass their query_ids.\n")
+			return
+		}
+		ids = []string{c.prevQueryID, c.lastQueryID}
+	case 2:
+	default:
+		fmt.Fprintf(c.term, "Usage: \\compare-events [query_id1 query_id2]\n")
+		return
+	}
+
+	events, err := c.fetchProfileEvents(ids[0], ids[1])
+	if err != nil {
+		c.printError(err)
+		return
+	}
+	for _, id := range ids {
+		if events[id] == nil {
+			fmt.Fprintf(c.term, "Query %s not found in system.query_log.\n\n", id)
+			return
+		}
+	}
+
+	first, second := events[ids[0]], events[ids[1]]
+	names := make(map[string]bool)
+	for name := range first {
+		names[name] = true
+	}
+	for name := range second {
+		names[name] = true
+	}
+
+	type eventDelta struct {
+		name          string
+		before, after uint64
+		delta         int64
+	}
+	var deltas []eventDelta
+	for name := range names {
+		before, after := first[name], second[name]
+		if before != after {
+			deltas = append(deltas, eventDelta{name, before, after, int64(after) - int64(before)})
+		}
+	}
+	if len(deltas) == 0 {
+		fmt.Fprintf(c.term, "No ProfileEvents counters differ.\n\n")
+		return
+	}
+
+	rank := make(map[string]int, len(keyProfileEvents))
+	for i, name := range keyProfileEvents {
+		rank[name] = i + 1
+	}
+	sort.Slice(deltas, func(i, j int) bool {
+		ri, rj := rank[deltas[i].name], rank[deltas[j].name]
+		if (ri > 0) != (rj > 0) {
+			return ri > 0
+		}
+		if ri != rj {
+			return ri < rj
+		}
+		return abs64(deltas[i].delta) > abs64(deltas[j].delta)
+	})
+
+	rs := &resultSet{cols: []string{"event", "query 1", "query 2", "delta", "change"}}
+	for _, d := range deltas {
+		name := d.name
+		if rank[name] > 0 {
+			name = "* " + name
+		}
+		change := "new"
+		if d.before > 0 {
+			change = fmt.Sprintf("%+.1f%%", float64(d.delta)*100/float64(d.before))
+		}
+		rs.rows = append(rs.rows, []interface{}{name, d.before, d.after, fmt.Sprintf("%+d", d.delta), change})
+	}
+
+	fmt.Fprintf(c.term, "query 1: %s\nquery 2: %s\n\n", ids[0], ids[1])
+	c.displayTable(c.term, rs)
+	fmt.Fprintf(c.term, "%d counters differ (* = key work counters).\n\n", len(deltas))
+}
+
+// fetchProfileEvents 刷新日志后读取指定查询的 ProfileEvents
+func (c *CLI) fetchProfileEvents(ids ...string) (map[string]map[string]uint64, error) {
+	ctx, cancel := c.queryContext()
+	defer cancel()
+
+	// query_log 异步落盘，先刷新；没有 SYSTEM 权限时忽略错误
+	c.db.ExecContext(ctx, "SYSTEM FLUSH LOGS")
+
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(ids)), ", ")
+
+	rows, err := c.db.QueryContext(ctx, `SELECT query_id, ProfileEvents
+FROM system.query_log
+WHERE type = 'QueryFinish' AND event_date >= yesterday() AND query_id IN (`+placeholders+`)`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make(map[string]map[string]uint64)
+	for rows.Next() {
+		var (
+			id     string
+			events map[string]uint64
+		)
+		if err := rows.Scan(&id, &events); err != nil {
+			return nil, err
+		}
+		result[id] = events
+	}
+	return result, rows.Err()
+}
+
+// abs64 返回整数绝对值
+func abs64(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
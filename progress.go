@@ -0,0 +1,146 @@
+package clickhouse
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+)
+
+// progressInterval 进度行的最小刷新间隔
+const progressInterval = 200 * time.Millisecond
+
+// writeProgress 累计 INSERT ... SELECT 执行期间服务端上报的写入量，并在同一行刷新显示
+type writeProgress struct {
+	w     io.Writer
+	start time.Time
+	last  time.Time
+	rows  uint64
+	bytes uint64
+	shown bool
+}
+
+// isInsertSelect 判断语句是否是 INSERT ... SELECT（写入量由服务端查询决定，可能很大）
+func isInsertSelect(sqlStr string) bool {
+	tokens := significantTokens(scanSQL(sqlStr))
+	if len(tokens) == 0 || !tokens[0].isKeyword("INSERT") {
+		return false
+	}
+	for _, t := range tokens[1:] {
+		if t.isKeyword("SELECT") {
+			return true
+		}
+	}
+	return false
+}
+
+// withWriteProgress 为 INSERT ... SELECT 注册进度回调，返回 nil 表示不显示进度
+func (c *CLI) withWriteProgress(ctx context.Context, sqlStr string) (context.Context, *writeProgress) {
+	if c.jsonOutput() || !isInsertSelect(sqlStr) {
+		return ctx, nil
+	}
+	p := &writeProgress{w: c.term, start: time.Now()}
+	// 驱动每个查询只保留一个进度回调，已注册读取统计时由它转发
+	if c.stats != nil {
+		c.stats.next = p.update
+		return ctx, p
+	}
+	return clickhouse.Context(ctx, clickhouse.WithProgress(p.update)), p
+}
+
+// update 累加进度增量，按 progressInterval 节流刷新进度行
+func (p *writeProgress) update(progress *clickhouse.Progress) {
+	p.rows += progress.WroteRows
+	p.bytes += progress.WroteBytes
+	if p.rows == 0 || time.Since(p.last) < progressInterval {
+		return
+	}
+	p.last = time.Now()
+	fmt.Fprintf(p.w, "\r\033[Kwritten %d rows, %s (%.0f rows/sec)",
+		p.rows, formatBytes(int64(p.bytes)), p.rate())
+	p.shown = true
+}
+
+// rate 返回平均写入速度（行/秒）
+func (p *writeProgress) rate() float64 {
+	elapsed := time.Since(p.start).Seconds()
+	if elapsed <= 0 {
+		return 0
+	}
+	return float64(p.rows) / elapsed
+}
+
+// clear 清除进度行，之后输出最终结果
+func (p *writeProgress) clear() {
+	if p.shown {
+		fmt.Fprintf(p.w, "\r\033[K")
+		p.shown = false
+	}
+}
+
+// readStats 累计服务端上报的读取量（rows_read / bytes_read），用于结果尾部的 Processed 统计
+type readStats struct {
+	rows    uint64
+	bytes   uint64
+	elapsed time.Duration              // 服务端上报的查询耗时，旧版本服务端不上报时为 0
+	next    func(*clickhouse.Progress) // 同一语句的其他进度回调，如写入进度
+}
+
+// withReadStats 为当前语句注册进度回调，累计服务端读取的行数和字节数
+func (c *CLI) withReadStats(ctx context.Context) context.Context {
+	c.stats = &readStats{}
+	return clickhouse.Context(ctx, clickhouse.WithProgress(c.stats.update))
+}
+
+// update 累加读取量增量并转发给其他进度回调；进度包中的耗时是从查询开始计算的，取最大值
+func (s *readStats) update(progress *clickhouse.Progress) {
+	s.rows += progress.Rows
+	s.bytes += progress.Bytes
+	s.elapsed = max(s.elapsed, progress.Elapsed)
+	if s.next != nil {
+		s.next(progress)
+	}
+}
+
+// processed 返回 clickhouse-client 风格的读取统计，
+// 如 "Processed 1.23 million rows, 45.60 MB (102.50 million rows/s., 3.80 GB/s.)"
+func (s *readStats) processed(elapsed float64) string {
+	rows := readableQuantity(float64(s.rows))
+	if s.rows < 1000 {
+		rows = fmt.Sprintf("%d", s.rows)
+	}
+	text := fmt.Sprintf("Processed %s rows, %s", rows, readableSize(float64(s.bytes)))
+	if elapsed > 0 {
+		text += fmt.Sprintf(" (%s rows/s., %s/s.)",
+			readableQuantity(float64(s.rows)/elapsed), readableSize(float64(s.bytes)/elapsed))
+	}
+	return text
+}
+
+// readableQuantity 以 thousand/million/billion/trillion 为单位格式化数量
+func readableQuantity(n float64) string {
+	unit := ""
+	for _, u := range []string{" thousand", " million", " billion", " trillion"} {
+		if n < 1000 {
+			break
+		}
+		n /= 1000
+		unit = u
+	}
+	return fmt.Sprintf("%.2f%s", n, unit)
+}
+
+// readableSize 以十进制单位（KB = 1000 B）格式化字节数，与 clickhouse-client 的统计一致
+func readableSize(n float64) string {
+	unit := "B"
+	for _, u := range []string{"KB", "MB", "GB", "TB", "PB"} {
+		if n < 1000 {
+			break
+		}
+		n /= 1000
+		unit = u
+	}
+	return fmt.Sprintf("%.2f %s", n, unit)
+}
@@ -0,0 +1,57 @@
+package clickhouse
+
+import (
+	"net"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// defaultPrompt 默认提示符模板，与 clickhouse-client 一致显示主机名
+const defaultPrompt = "{tx}{host} :) "
+
+// getPrompt 按 Config.Prompt 模板生成提示符，支持 {host}、{port}、{user}、{db}，
+// {tx} 在服务端事务进行中时展开为 "[tx] "
+func (c *CLI) getPrompt() string {
+	template := c.config.Prompt
+	if template == "" {
+		template = defaultPrompt
+	}
+	host := c.host
+	if c.socket != "" {
+		host = c.socket
+	} else if addr := c.connectedAddr(); c.multiHost() && addr != "" {
+		host, _, _ = net.SplitHostPort(addr)
+	}
+	tx := ""
+	if c.inTransaction {
+		tx = "[tx] "
+	}
+	return strings.NewReplacer(
+		"{host}", host,
+		"{port}", strconv.Itoa(c.port),
+		"{user}", c.username,
+		"{db}", c.database,
+		"{tx}", tx,
+	).Replace(template)
+}
+
+// continuationPrompt 返回多行输入的续行提示符，右对齐到主提示符的宽度；
+// 输入停在未闭合的字符串、引用标识符或块注释中时，用 '、`、" 或 * 代替冒号提示
+func (c *CLI) continuationPrompt(input string) string {
+	marker := ":"
+	if tokens := scanSQL(input); len(tokens) > 0 && tokens[len(tokens)-1].open {
+		switch last := tokens[len(tokens)-1]; last.kind {
+		case tokenString, tokenQuoted:
+			marker = last.text[:1]
+		case tokenComment:
+			marker = "*"
+		}
+	}
+	prompt := marker + "-] "
+	width := utf8.RuneCountInString(c.getPrompt())
+	if pad := width - len(prompt); pad > 0 {
+		prompt = strings.Repeat(" ", pad) + prompt
+	}
+	return prompt
+}
@@ -0,0 +1,83 @@
+package clickhouse
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// queryLogEntry 审计日志中的一条记录，每条语句写一行 JSON
+type queryLogEntry struct {
+	Time      string `json:"time"`
+	QueryID   string `json:"query_id"`
+	User      string `json:"user"`
+	Host      string `json:"host"`
+	Database  string `json:"database"`
+	ElapsedMs int64  `json:"elapsed_ms"`
+	Status    string `json:"status"`
+	Error     string `json:"error,omitempty"`
+	Query     string `json:"query"`
+}
+
+// setQueryLog 处理 \querylog <path>|off：将之后执行的每条语句追加写入审计文件
+func (c *CLI) setQueryLog(args string) {
+	switch {
+	case args == "":
+		if c.queryLog == nil {
+			fmt.Fprintf(c.term, "Query log is off.\n")
+		} else {
+			fmt.Fprintf(c.term, "Logging statements to %s\n", c.queryLog.Name())
+		}
+		return
+	case strings.EqualFold(args, "off"):
+		c.closeQueryLog()
+		fmt.Fprintf(c.term, "Query log disabled.\n")
+		return
+	}
+
+	f, err := os.OpenFile(expandHome(args), os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		fmt.Fprintf(c.term, "Cannot open query log: %v\n", err)
+		return
+	}
+	c.closeQueryLog()
+	c.queryLog = f
+	fmt.Fprintf(c.term, "Logging statements to %s\n", f.Name())
+}
+
+// closeQueryLog 关闭审计文件
+func (c *CLI) closeQueryLog() {
+	if c.queryLog != nil {
+		c.queryLog.Close()
+		c.queryLog = nil
+	}
+}
+
+// logStatement 追加一条审计记录；文件不带缓冲，每条语句写完即落到操作系统
+func (c *CLI) logStatement(sqlStr string, startTime time.Time, err error) {
+	if c.queryLog == nil {
+		return
+	}
+
+	entry := queryLogEntry{
+		Time:      startTime.Format(time.RFC3339Nano),
+		QueryID:   c.lastQueryID,
+		User:      c.username,
+		Host:      c.addr(),
+		Database:  c.database,
+		ElapsedMs: time.Since(startTime).Milliseconds(),
+		Status:    "ok",
+		Query:     sqlStr,
+	}
+	if err != nil {
+		entry.Status = "error"
+		entry.Error = err.Error()
+	}
+
+	data, _ := json.Marshal(entry)
+	if _, err := c.queryLog.Write(append(data, '\n')); err != nil {
+		fmt.Fprintf(c.term, "Warning: failed to write query log: %v\n", err)
+	}
+}
@@ -1,14 +1,39 @@
 package clickhouse
 
 import (
+	"bytes"
 	"io"
-	
+	"sync/atomic"
+
 	"github.com/chzyer/readline"
 )
 
+// ctrlC 终端输入中 Ctrl-C 对应的字节
+const ctrlC = 0x03
+
 // ReadWriteCloser wraps io.ReadWriter to add a no-op Close method
 type ReadWriteCloser struct {
 	io.ReadWriter
+	interrupt func() bool // 输入中出现 Ctrl-C 时调用，返回 true 表示已处理，该字节不再交给 readline
+}
+
+// Read 读取终端输入；interrupt 处理了 Ctrl-C 时把它从输入中去掉
+func (rwc *ReadWriteCloser) Read(p []byte) (int, error) {
+	for {
+		n, err := rwc.ReadWriter.Read(p)
+		if rwc.interrupt == nil || bytes.IndexByte(p[:n], ctrlC) < 0 || !rwc.interrupt() {
+			return n, err
+		}
+		kept := p[:0]
+		for _, b := range p[:n] {
+			if b != ctrlC {
+				kept = append(kept, b)
+			}
+		}
+		if len(kept) > 0 || err != nil {
+			return len(kept), err
+		}
+	}
 }
 
 func (rwc *ReadWriteCloser) Close() error {
@@ -17,30 +42,91 @@ func (rwc *ReadWriteCloser) Close() error {
 
 // Reader 从终端读取输入（使用 readline 以支持SSH session）
 type Reader struct {
-	rl *readline.Instance
+	rl      *readline.Instance
+	rwc     *ReadWriteCloser
+	initial string      // 下一次 ReadLine 预先填入的内容
+	reading atomic.Bool // 是否正在 ReadLine/ReadPassword 中等待输入
 }
 
 // NewReader 创建新的 Reader
 func NewReader(term io.ReadWriter) *Reader {
-	rwc := &ReadWriteCloser{term}
+	rwc := &ReadWriteCloser{ReadWriter: term}
 	rl, err := readline.NewEx(&readline.Config{
-		Stdin:  rwc,
-		Stdout: rwc,
-		Prompt: "",
+		Stdin:           rwc,
+		Stdout:          rwc,
+		Prompt:          "",
 		InterruptPrompt: "^C",
-		EOFPrompt: "exit",
+		EOFPrompt:       "exit",
+		// 历史由 CLI 按完整语句保存，多行 SQL 作为一条记录
+		DisableAutoSaveHistory: true,
 	})
 	if err != nil {
 		panic(err)
 	}
-	return &Reader{rl: rl}
+	return &Reader{rl: rl, rwc: rwc}
+}
+
+// SetInterrupt 设置不在读取输入时（如语句执行期间）终端输入中 Ctrl-C 的处理函数，返回 false 时照常交给 readline；
+// 自定义 Terminal（如 SSH 会话）的 Ctrl-C 只以输入字节的形式到达，不会产生 SIGINT
+func (r *Reader) SetInterrupt(f func() bool) {
+	r.rwc.interrupt = func() bool {
+		return !r.reading.Load() && f()
+	}
 }
 
 // ReadLine 读取一行输入
 func (r *Reader) ReadLine() (string, error) {
+	r.reading.Store(true)
+	defer r.reading.Store(false)
+	if r.initial != "" {
+		initial := r.initial
+		r.initial = ""
+		return r.rl.ReadlineWithDefault(initial)
+	}
 	return r.rl.Readline()
 }
 
+// SaveHistory 添加一条历史记录，可以包含换行
+func (r *Reader) SaveHistory(entry string) {
+	r.rl.SaveHistory(entry)
+}
+
+// SetCompleter 设置 Tab 补全
+func (r *Reader) SetCompleter(completer readline.AutoCompleter) {
+	r.rl.Config.AutoComplete = completer
+}
+
+// Print 在不破坏当前输入行的情况下输出文本（用于补全时显示提示信息）
+func (r *Reader) Print(text string) {
+	r.rl.Write([]byte(text))
+}
+
+// SetInitial 设置下一次 ReadLine 的初始内容，用户可以编辑后再回车
+func (r *Reader) SetInitial(text string) {
+	r.initial = text
+}
+
+// WriteStdin 将数据作为用户输入交给 readline
+func (r *Reader) WriteStdin(b []byte) {
+	r.rl.WriteStdin(b)
+}
+
+// ReadPassword 关闭回显读取密码，输入不会进入历史记录
+func (r *Reader) ReadPassword(prompt string) (string, error) {
+	r.reading.Store(true)
+	defer r.reading.Store(false)
+	b, err := r.rl.ReadPassword(prompt)
+	return string(b), err
+}
+
+// Width 返回终端宽度，无法获取时返回 0
+func (r *Reader) Width() int {
+	if w := r.rl.Config.FuncGetWidth(); w > 0 {
+		return w
+	}
+	return 0
+}
+
 // SetPrompt 设置提示符
 func (r *Reader) SetPrompt(prompt string) {
 	r.rl.SetPrompt(prompt)
@@ -0,0 +1,32 @@
+package clickhouse
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+func TestReadWriteCloserInterrupt(t *testing.T) {
+	var handled int
+	consume := true
+	rwc := &ReadWriteCloser{
+		ReadWriter: &bytes.Buffer{},
+		interrupt: func() bool {
+			handled++
+			return consume
+		},
+	}
+
+	rwc.ReadWriter.(*bytes.Buffer).WriteString("ab\x03c")
+	got, _ := io.ReadAll(rwc)
+	if string(got) != "abc" || handled != 1 {
+		t.Errorf("consumed Ctrl-C: read %q, handled %d; want \"abc\", 1", got, handled)
+	}
+
+	consume = false
+	rwc.ReadWriter.(*bytes.Buffer).WriteString("\x03")
+	got, _ = io.ReadAll(rwc)
+	if string(got) != "\x03" || handled != 2 {
+		t.Errorf("unhandled Ctrl-C: read %q, handled %d; want \"\\x03\", 2", got, handled)
+	}
+}
@@ -0,0 +1,122 @@
+package clickhouse
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"syscall"
+	"time"
+)
+
+// reconnect 处理 \reconnect：用当前连接参数重新连接，恢复会话设置，并重新执行因断线失败而保留的语句
+func (c *CLI) reconnect() {
+	if err := c.reopen(); err != nil {
+		c.printError(err)
+		return
+	}
+	fmt.Fprintf(c.term, "Reconnected to %s.\n", c.addr())
+	c.restoreSession()
+
+	if c.pendingSQL != "" {
+		sqlStr := c.pendingSQL
+		c.pendingSQL = ""
+		fmt.Fprintf(c.term, "Retrying: %s\n", sqlStr)
+		c.executeSQL(sqlStr)
+	}
+}
+
+// reopen 建立新的连接池，成功后关闭旧连接池；失败时保留旧连接池
+func (c *CLI) reopen() error {
+	oldDB := c.db
+	if err := c.open(); err != nil {
+		return err
+	}
+	if oldDB != nil {
+		oldDB.Close()
+	}
+	c.schema.reset()
+	return nil
+}
+
+// retryDisconnected 包装 fn：因连接断开失败时自动重连（只尝试一次）并输出重连结果。
+// 只有连接阶段的失败（拨号失败、连接被拒绝，语句肯定没有到达服务端）才自动重试一次；
+// 语句发出后连接断开或读取超时时服务端可能已经执行了它（如 INSERT），只重连并报告错误，不再重复执行。
+// 重连失败或重试仍断线时标记 c.disconnected，由 executeSQL 保留语句供 \reconnect 重放
+func (c *CLI) retryDisconnected(fn func() error) func() error {
+	return func() error {
+		err := fn()
+		if !isConnectionError(err) || c.cancelled() {
+			return err
+		}
+
+		if !c.jsonOutput() {
+			fmt.Fprintf(c.term, "Connection lost, reconnecting...\n")
+		}
+		if rerr := c.reopen(); rerr != nil {
+			if !c.jsonOutput() {
+				fmt.Fprintf(c.term, "Reconnect to %s failed: %v\n", c.addr(), rerr)
+			}
+			c.disconnected = true
+			return err
+		}
+		if !isConnectFailure(err) {
+			if !c.jsonOutput() {
+				fmt.Fprintf(c.term, "Reconnected to %s. The statement may have reached the server, so it was not run again.\n", c.addr())
+			}
+			return err
+		}
+		if !c.jsonOutput() {
+			fmt.Fprintf(c.term, "Reconnected to %s.\n", c.addr())
+		}
+
+		err = fn()
+		c.disconnected = isConnectionError(err)
+		return err
+	}
+}
+
+// isConnectionError 判断错误是否由连接断开或无法建立连接引起（而非服务端返回的异常或超时）
+func isConnectionError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if _, ok := serverException(err); ok {
+		return false
+	}
+	return isConnectFailure(err) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.EPIPE)
+}
+
+// isConnectFailure 判断错误是否发生在建立连接阶段（拨号失败、连接被拒绝、驱动在发送前发现连接已失效），
+// 此时语句没有发送到服务端，可以安全地重新执行
+func isConnectFailure(err error) bool {
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, syscall.ECONNREFUSED) {
+		return true
+	}
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// waitServerInterval 等待服务端启动时两次连接尝试的间隔
+const waitServerInterval = time.Second
+
+// waitForServer 在 timeout 内反复尝试连接，每次失败输出一个点，用于容器启动时等待服务端就绪
+func (c *CLI) waitForServer(timeout time.Duration) error {
+	fmt.Fprintf(c.term, "Waiting for %s", c.addr())
+	deadline := time.Now().Add(timeout)
+	for {
+		err := c.open()
+		if err == nil {
+			fmt.Fprintf(c.term, " ready\n")
+			return nil
+		}
+		fmt.Fprintf(c.term, ".")
+		if time.Now().Add(waitServerInterval).After(deadline) {
+			fmt.Fprintf(c.term, "\n")
+			return fmt.Errorf("server %s not reachable after %s: %w", c.addr(), timeout, err)
+		}
+		time.Sleep(waitServerInterval)
+	}
+}
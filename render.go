@@ -0,0 +1,176 @@
+package clickhouse
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 结果渲染方式
+const (
+	renderClient = "client" // 由 CLI 自己的表格/垂直等渲染器输出（原生协议）
+	renderServer = "server" // 通过 HTTP 接口由服务端按 FORMAT 生成输出，原样写到终端
+)
+
+// defaultHTTPPort ClickHouse HTTP 接口的默认端口
+const defaultHTTPPort = 8123
+
+// setRender 处理 \render server [http_port] | client
+func (c *CLI) setRender(args string) {
+	fields := strings.Fields(args)
+	if len(fields) == 0 {
+		fmt.Fprintf(c.term, "Rendering: %s", c.render)
+		if c.render == renderServer {
+			fmt.Fprintf(c.term, " (HTTP port %d)", c.httpPort)
+		}
+		fmt.Fprintf(c.term, "\n")
+		return
+	}
+
+	switch mode := strings.ToLower(fields[0]); {
+	case mode == renderClient && len(fields) == 1:
+		c.render = renderClient
+		fmt.Fprintf(c.term, "Results are rendered by the client.\n")
+	case mode == renderServer && len(fields) <= 2:
+		if len(fields) == 2 {
+			port, err := strconv.Atoi(fields[1])
+			if err != nil || port <= 0 {
+				fmt.Fprintf(c.term, "Invalid port: %s\n", fields[1])
+				return
+			}
+			c.httpPort = port
+		}
+		c.render = renderServer
+		fmt.Fprintf(c.term, "Results are rendered by the server over HTTP port %d.\n", c.httpPort)
+	default:
+		fmt.Fprintf(c.term, "Usage: \\render server [http_port] | client\n")
+	}
+}
+
+// executeServerRendered 通过 HTTP 接口执行查询，把服务端按 FORMAT 生成的输出原样写到终端。
+// 没有 FORMAT 子句时按当前显示模式追加 PrettyCompact 或 Vertical（与官方客户端的默认输出一致）。
+// 原生协议连接下只有 \render server 时才另开 HTTP 请求，否则报错而不是悄悄换用 HTTP 接口
+func (c *CLI) executeServerRendered(ctx context.Context, sqlStr string, startTime time.Time) {
+	if c.render != renderServer && !c.viaHTTP() {
+		name, _ := trailingFormat(sqlStr)
+		c.printError(fmt.Errorf("FORMAT %s is produced by the server and needs the HTTP interface, but the connection uses the native protocol; "+
+			"run \\render server [http_port] or set Config.Protocol to http", name))
+		return
+	}
+	if !hasFormatClause(sqlStr) {
+		format := "PrettyCompact"
+		if c.verticalMode {
+			format = "Vertical"
+		}
+		sqlStr += "\nFORMAT " + format
+	}
+
+	params := url.Values{}
+	params.Set("database", c.database)
+	params.Set("query_id", c.lastQueryID)
+	for name, value := range c.settings {
+		params.Set(name, value)
+	}
+	for name, value := range c.queryParams {
+		params.Set("param_"+name, value)
+	}
+	endpoint, client, err := c.httpEndpoint()
+	if err != nil {
+		c.printError(err)
+		return
+	}
+	endpoint.RawQuery = params.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.String(), strings.NewReader(sqlStr))
+	if err != nil {
+		c.printError(err)
+		return
+	}
+	req.Header.Set("X-ClickHouse-User", c.username)
+	req.Header.Set("X-ClickHouse-Key", c.password)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		c.printError(fmt.Errorf("HTTP interface at %s is not reachable (set Config.HTTPPort or run \\render server <port>): %w",
+			endpoint.Host, err))
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		if exception, ok := parseHTTPException(string(body)); ok {
+			c.printError(exception)
+		} else {
+			c.printError(errors.New(strings.TrimSpace(string(body))))
+		}
+		return
+	}
+	if _, err := io.Copy(c.term, resp.Body); err != nil {
+		c.printError(err)
+		return
+	}
+
+	if c.timingEnabled {
+		fmt.Fprintf(c.term, "Elapsed: %.3f sec.\n", time.Since(startTime).Seconds())
+	}
+	fmt.Fprintf(c.term, "\n")
+}
+
+// httpEndpoint 根据当前连接设置得到 server 渲染使用的 HTTP 接口地址和客户端：主机取实际连上的主机（多主机时），
+// 端口为 HTTP 端口，Secure 时使用 https 并沿用连接的 TLS 设置。Unix 套接字连接无法推出 HTTP 接口地址，返回错误
+func (c *CLI) httpEndpoint() (*url.URL, *http.Client, error) {
+	if c.socket != "" {
+		return nil, nil, fmt.Errorf("server-side rendering needs the HTTP interface, which cannot be reached through the unix socket %s; "+
+			"connect with Host/Port or run \\render client", c.socket)
+	}
+	opts, err := c.connOptions()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	host := c.host
+	if addr := c.connectedAddr(); addr != "" {
+		host, _, _ = net.SplitHostPort(addr)
+	}
+	endpoint := &url.URL{
+		Scheme: "http",
+		Host:   net.JoinHostPort(host, strconv.Itoa(c.httpPort)),
+		Path:   "/",
+	}
+	client := http.DefaultClient
+	if opts.TLS != nil {
+		endpoint.Scheme = "https"
+		client = &http.Client{Transport: &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: opts.TLS.Clone(),
+		}}
+	}
+	return endpoint, client, nil
+}
+
+// serverFormatted 判断查询是否以客户端无法生成的 FORMAT 子句结尾（如 Pretty、Vertical、JSONEachRow、Markdown），
+// 这类查询交给服务端按该格式输出并原样显示；JSON、CSV、TSV 仍由客户端生成，INTO OUTFILE 写入文件
+func serverFormatted(sqlStr string) bool {
+	if !hasFormatClause(sqlStr) {
+		return false
+	}
+	if _, format := splitOutputFormat(sqlStr); format != "" {
+		return false
+	}
+	_, out, err := splitOutfile(sqlStr)
+	return err == nil && out == nil
+}
+
+// hasFormatClause 判断语句末尾是否有顶层的 FORMAT 子句
+func hasFormatClause(sqlStr string) bool {
+	name, _ := trailingFormat(sqlStr)
+	return name != ""
+}
@@ -0,0 +1,102 @@
+package clickhouse
+
+import (
+	"fmt"
+	"strings"
+)
+
+// 副本健康判断阈值
+const (
+	replicaDelayWarn       = 300 // absolute_delay 超过 5 分钟视为落后
+	replicaQueueWarn       = 100 // 复制队列积压
+	replicaFuturePartsWarn = 20  // 等待生成的 part 过多，通常是拉取或合并跟不上
+)
+
+// showReplication 汇总 system.replicas 和 system.replication_queue，
+// 显示每个复制表的队列、延迟、leader/只读状态，并给出健康结论
+func (c *CLI) showReplication(table string) {
+	where, args := "", []interface{}{}
+	if table != "" {
+		database, name := splitQualifiedName(table, c.database)
+		where = "WHERE r.database = ? AND r.table = ?"
+		args = append(args, database, name)
+	}
+
+	rs, err := c.runQuery(fmt.Sprintf(`SELECT
+    r.database || '.' || r.table AS table,
+    r.is_leader AS leader,
+    r.is_readonly AS readonly,
+    r.is_session_expired AS session_expired,
+    r.queue_size AS queue,
+    r.inserts_in_queue AS inserts,
+    r.merges_in_queue AS merges,
+    r.future_parts AS future_parts,
+    r.absolute_delay AS delay_s,
+    q.failing AS failing,
+    multiIf(
+        r.is_session_expired, 'SESSION EXPIRED',
+        r.is_readonly, 'READONLY',
+        r.absolute_delay > %[1]d, 'LAGGING',
+        q.failing > 0, 'FAILING',
+        r.queue_size > %[2]d OR r.future_parts > %[3]d, 'BACKLOG',
+        'OK') AS health
+FROM system.replicas AS r
+LEFT JOIN (
+    SELECT database, table, countIf(last_exception != '') AS failing
+    FROM system.replication_queue
+    GROUP BY database, table
+) AS q ON q.database = r.database AND q.table = r.table
+%[4]s
+ORDER BY health = 'OK', r.absolute_delay DESC, table`,
+		replicaDelayWarn, replicaQueueWarn, replicaFuturePartsWarn, where), args...)
+	if err != nil {
+		c.printError(err)
+		return
+	}
+	if len(rs.rows) == 0 {
+		if table != "" {
+			fmt.Fprintf(c.term, "%s is not a replicated table.\n\n", table)
+		} else {
+			fmt.Fprintf(c.term, "No replicated tables on this server.\n\n")
+		}
+		return
+	}
+
+	c.displayTable(c.term, rs)
+
+	var unhealthy []string
+	for _, row := range rs.rows {
+		if health := formatValue(row[len(row)-1]); health != "OK" {
+			unhealthy = append(unhealthy, fmt.Sprintf("%s (%s)", formatValue(row[0]), health))
+		}
+	}
+	if len(unhealthy) == 0 {
+		fmt.Fprintf(c.term, "Verdict: all %d replicas healthy.\n\n", len(rs.rows))
+		return
+	}
+	fmt.Fprintf(c.term, "Verdict: %d of %d replicas need attention: %s\n",
+		len(unhealthy), len(rs.rows), strings.Join(unhealthy, ", "))
+	c.showReplicationErrors(where, args)
+}
+
+// showReplicationErrors 显示复制队列中重试最多的失败任务及其最后一次错误
+func (c *CLI) showReplicationErrors(where string, args []interface{}) {
+	where = strings.Replace(where, "WHERE", "AND", 1)
+	rs, err := c.runQuery(`SELECT
+    r.database || '.' || r.table AS table,
+    r.type,
+    r.new_part_name AS part,
+    r.num_tries AS tries,
+    r.last_exception
+FROM system.replication_queue AS r
+WHERE r.last_exception != '' `+where+`
+ORDER BY r.num_tries DESC
+LIMIT 5`, args...)
+	if err != nil || len(rs.rows) == 0 {
+		fmt.Fprintf(c.term, "\n")
+		return
+	}
+	fmt.Fprintf(c.term, "\nMost retried failing queue entries:\n")
+	c.displayTable(c.term, rs)
+	fmt.Fprintf(c.term, "\n")
+}
@@ -0,0 +1,39 @@
+package clickhouse
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// 服务端繁忙重试的默认值
+const (
+	defaultBusyRetries    = 3
+	defaultBusyRetryDelay = time.Second
+)
+
+// isServerBusy 判断错误是否为 TOO_MANY_SIMULTANEOUS_QUERIES
+func isServerBusy(err error) bool {
+	exception, ok := serverException(err)
+	return ok && exception.Code == codeTooManySimultaneousQueries
+}
+
+// retryBusy 执行 fn，遇到 TOO_MANY_SIMULTANEOUS_QUERIES 时等待后重试，最多 c.busyRetries 次
+// 该错误在查询开始执行前返回，重试对读写语句都是安全的；等待时间随次数线性增加
+func (c *CLI) retryBusy(ctx context.Context, fn func() error) error {
+	err := fn()
+	for attempt := 1; attempt <= c.busyRetries && isServerBusy(err); attempt++ {
+		delay := c.busyDelay * time.Duration(attempt)
+		if !c.jsonOutput() {
+			fmt.Fprintf(c.term, "Server busy, retrying in %s (%d/%d)...\n", delay, attempt, c.busyRetries)
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+		err = fn()
+	}
+	return err
+}
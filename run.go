@@ -0,0 +1,79 @@
+package clickhouse
+
+import (
+	"context"
+	"io"
+	"strings"
+)
+
+// RunQuery 非交互地执行 sqlStr 中以分号分隔的语句（可包含独占一行的元命令），用于脚本和定时任务。
+// sqlStr 为空时从终端读取全部输入直到 EOF。尚未连接时静默连接，不显示欢迎信息和提示符；
+// 结果按当前输出设置打印。遇到第一个失败的语句即停止并返回其错误，调用方据此设置退出码；取消 ctx 会中止当前语句
+func (c *CLI) RunQuery(ctx context.Context, sqlStr string) error {
+	if c.db == nil {
+		if err := c.connect(false); err != nil {
+			return err
+		}
+	}
+
+	if strings.TrimSpace(sqlStr) == "" {
+		input, err := c.readInput()
+		if err != nil {
+			return err
+		}
+		sqlStr = input
+	}
+
+	prev := c.stmtCtx
+	c.stmtCtx = ctx
+	defer func() { c.stmtCtx = prev }()
+
+	for _, stmt := range splitStatements(sqlStr) {
+		c.lastErr = nil
+		c.runStatement(stmt)
+		if c.lastErr != nil {
+			return c.lastErr
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RunScript 非交互地执行 r 中的脚本，规则与 \i 和启动脚本相同：元命令独占一行，SQL 以分号结束。
+// 尚未连接时静默连接。Config.StopOnError 时遇到第一个失败的语句即停止并返回其错误，
+// 否则执行全部语句，只返回读取错误；脚本读完返回 nil。取消 ctx 会中止当前语句并返回 ctx 的错误，
+// 语句被 Ctrl-C 中断后批处理中止时返回 "batch aborted" 错误
+func (c *CLI) RunScript(ctx context.Context, r io.Reader) error {
+	if c.db == nil {
+		if err := c.connect(false); err != nil {
+			return err
+		}
+	}
+
+	prev := c.stmtCtx
+	c.stmtCtx = ctx
+	defer func() { c.stmtCtx = prev }()
+
+	if err := c.runScript(r); err != nil {
+		return err
+	}
+	return ctx.Err()
+}
+
+// readInput 不显示提示符地读取终端的全部输入直到 EOF
+func (c *CLI) readInput() (string, error) {
+	c.reader.SetPrompt("")
+	var lines []string
+	for {
+		line, err := c.reader.ReadLine()
+		if err == io.EOF {
+			return strings.Join(lines, "\n"), nil
+		}
+		if err != nil {
+			return "", err
+		}
+		lines = append(lines, line)
+	}
+}
@@ -0,0 +1,202 @@
+package clickhouse
+
+import "sync"
+
+// defaultSchemaExclude 默认不加载字段元数据的系统库，它们的 system.columns 可能非常大
+var defaultSchemaExclude = []string{"system", "INFORMATION_SCHEMA", "information_schema"}
+
+// schemaCache 按数据库懒加载的表结构缓存，供补全和结构查询使用
+// 只在第一次用到某个数据库时查询 system.tables / system.columns，且查询限定在该库
+type schemaCache struct {
+	mu        sync.Mutex
+	exclude   map[string]bool
+	databases []string
+	tables    map[string][]string
+	columns   map[string]map[string][]columnInfo
+	settings  []settingInfo
+	functions []string
+}
+
+// settingInfo system.settings 中的一个设置
+type settingInfo struct {
+	name        string
+	value       string
+	description string
+}
+
+// newSchemaCache 创建缓存，exclude 为空时使用 defaultSchemaExclude
+func newSchemaCache(exclude []string) *schemaCache {
+	if len(exclude) == 0 {
+		exclude = defaultSchemaExclude
+	}
+	s := &schemaCache{exclude: make(map[string]bool, len(exclude))}
+	for _, db := range exclude {
+		s.exclude[db] = true
+	}
+	s.reset()
+	return s
+}
+
+// reset 清空已加载的元数据，切换连接或执行 DDL 后调用
+func (s *schemaCache) reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.databases = nil
+	s.settings = nil
+	s.functions = nil
+	s.tables = make(map[string][]string)
+	s.columns = make(map[string]map[string][]columnInfo)
+}
+
+// schemaDatabases 返回数据库列表，首次调用时加载
+func (c *CLI) schemaDatabases() ([]string, error) {
+	c.schema.mu.Lock()
+	defer c.schema.mu.Unlock()
+
+	if c.schema.databases != nil {
+		return c.schema.databases, nil
+	}
+	names, err := c.queryStrings("SELECT name FROM system.databases ORDER BY name")
+	if err != nil {
+		return nil, err
+	}
+	c.schema.databases = names
+	return names, nil
+}
+
+// schemaTables 返回指定数据库的表名，首次用到该库时加载
+func (c *CLI) schemaTables(database string) ([]string, error) {
+	c.schema.mu.Lock()
+	defer c.schema.mu.Unlock()
+
+	if tables, ok := c.schema.tables[database]; ok {
+		return tables, nil
+	}
+	names, err := c.queryStrings("SELECT name FROM system.tables WHERE database = ? ORDER BY name", database)
+	if err != nil {
+		return nil, err
+	}
+	c.schema.tables[database] = names
+	return names, nil
+}
+
+// schemaColumns 返回指定数据库中指定表的字段，首次用到该库时一次性加载整个库的字段
+// 排除列表中的数据库不加载，返回 nil
+func (c *CLI) schemaColumns(database, table string) ([]columnInfo, error) {
+	c.schema.mu.Lock()
+	defer c.schema.mu.Unlock()
+
+	if c.schema.exclude[database] {
+		return nil, nil
+	}
+	if tables, ok := c.schema.columns[database]; ok {
+		return tables[table], nil
+	}
+
+	ctx, cancel := c.queryContext()
+	defer cancel()
+
+	rows, err := c.db.QueryContext(ctx,
+		"SELECT table, name, type FROM system.columns WHERE database = ? ORDER BY table, position", database)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tables := make(map[string][]columnInfo)
+	for rows.Next() {
+		var tbl string
+		var col columnInfo
+		if err := rows.Scan(&tbl, &col.name, &col.typ); err != nil {
+			return nil, err
+		}
+		tables[tbl] = append(tables[tbl], col)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	c.schema.columns[database] = tables
+	return tables[table], nil
+}
+
+// schemaSettings 返回服务端的设置列表（名称、当前值、说明），首次调用时加载
+func (c *CLI) schemaSettings() ([]settingInfo, error) {
+	c.schema.mu.Lock()
+	defer c.schema.mu.Unlock()
+
+	if c.schema.settings != nil {
+		return c.schema.settings, nil
+	}
+
+	ctx, cancel := c.queryContext()
+	defer cancel()
+
+	rows, err := c.db.QueryContext(ctx, "SELECT name, value, description FROM system.settings ORDER BY name")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	settings := []settingInfo{}
+	for rows.Next() {
+		var s settingInfo
+		if err := rows.Scan(&s.name, &s.value, &s.description); err != nil {
+			return nil, err
+		}
+		settings = append(settings, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	c.schema.settings = settings
+	return settings, nil
+}
+
+// schemaFunctions 返回服务端函数名列表，首次调用时加载
+func (c *CLI) schemaFunctions() ([]string, error) {
+	c.schema.mu.Lock()
+	defer c.schema.mu.Unlock()
+
+	if c.schema.functions != nil {
+		return c.schema.functions, nil
+	}
+	names, err := c.queryStrings("SELECT name FROM system.functions ORDER BY name")
+	if err != nil {
+		return nil, err
+	}
+	c.schema.functions = names
+	return names, nil
+}
+
+// queryStrings 执行返回单个字符串列的查询，不受 maxRows 限制
+func (c *CLI) queryStrings(query string, args ...interface{}) ([]string, error) {
+	ctx, cancel := c.queryContext()
+	defer cancel()
+
+	rows, err := c.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	values := []string{}
+	for rows.Next() {
+		var v string
+		if err := rows.Scan(&v); err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+	}
+	return values, rows.Err()
+}
+
+// isSchemaChange 判断语句是否可能修改表结构，需要清空 schemaCache
+func isSchemaChange(sqlStr string) bool {
+	switch firstKeyword(sqlStr) {
+	case "CREATE", "DROP", "ALTER", "RENAME", "EXCHANGE", "ATTACH", "DETACH":
+		return true
+	}
+	return false
+}
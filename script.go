@@ -0,0 +1,183 @@
+package clickhouse
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// defaultInitFile 默认的启动脚本文件名（位于用户主目录）
+const defaultInitFile = ".clickhouse-cli.rc"
+
+// runInitFile 执行启动脚本，未显式指定且默认文件不存在时跳过。
+// 只有 StopOnError 时才返回错误（包括第一个失败的语句），否则错误只输出提示
+func (c *CLI) runInitFile() error {
+	path := c.initFile
+	if path == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil
+		}
+		path = filepath.Join(home, defaultInitFile)
+		if _, err := os.Stat(path); err != nil {
+			return nil
+		}
+	}
+
+	f, err := os.Open(expandHome(path))
+	if err == nil {
+		defer f.Close()
+		err = c.runScript(f)
+	}
+	if err != nil && c.stopOnError {
+		return err
+	}
+	// 中止时 runScript 已经提示过
+	if err != nil && !errors.Is(err, errBatchAborted) {
+		fmt.Fprintf(c.term, "Failed to read init file %s: %v\n\n", path, err)
+	}
+	return nil
+}
+
+// runScript 按与 \i 相同的规则执行脚本：元命令独占一行，SQL 以分号结束，可跨多行，一行也可以有多条语句；
+// Ctrl-C 只取消当前语句，可选择跳过、重试或中止，中止时返回 errBatchAborted。
+// StopOnError 时遇到第一个失败的语句即停止并返回其错误
+func (c *CLI) runScript(r io.Reader) error {
+	prev := c.inScript
+	c.inScript = true
+	defer func() { c.inScript = prev }()
+
+	return readScript(r, func(stmt string) error {
+		c.lastErr = nil
+		if !c.runBatchStatement(stmt) {
+			fmt.Fprintf(c.term, "Batch aborted.\n\n")
+			return errBatchAborted
+		}
+		if c.stopOnError && c.lastErr != nil {
+			return c.lastErr
+		}
+		if c.cancelled() {
+			return c.stmtCtx.Err()
+		}
+		return nil
+	})
+}
+
+// readScript 逐行读取脚本，语句完整（结尾分号不在字符串或注释中）时按 splitStatements 切分并依次交给 run，
+// 语句开头独占一行的元命令直接执行；run 返回错误时停止读取并返回该错误
+func readScript(r io.Reader, run func(stmt string) error) error {
+	var lines []string
+	flush := func() error {
+		stmts := splitStatements(strings.Join(lines, "\n"))
+		lines = nil
+		for _, stmt := range stmts {
+			if err := run(stmt); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+		if len(lines) == 0 && (trimmed == "" || strings.HasPrefix(trimmed, "--")) {
+			continue
+		}
+
+		lines = append(lines, line)
+		meta := len(lines) == 1 && strings.HasPrefix(trimmed, "\\")
+		if meta || statementComplete(strings.Join(lines, "\n")) {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+	if err := flush(); err != nil {
+		return err
+	}
+	return scanner.Err()
+}
+
+// includeEchoWidth \i 回显语句时的最大宽度
+const includeEchoWidth = 70
+
+// includeFile 处理 \i [--continue] <path> 和 source <path>：按分号切分文件（跳过字符串和注释中的分号）并依次执行，
+// 每条语句前回显序号和语句开头；默认遇到第一个错误即停止，--continue 时继续执行后续语句。
+// Ctrl-C 与启动脚本一样只取消当前语句，由用户选择跳过、重试还是中止
+func (c *CLI) includeFile(args string) {
+	cont := false
+	if rest, ok := strings.CutPrefix(args, "--continue"); ok {
+		cont = true
+		args = strings.TrimSpace(rest)
+	}
+	if args == "" {
+		fmt.Fprintf(c.term, "Usage: \\i [--continue] <path>\n")
+		return
+	}
+
+	data, err := os.ReadFile(expandHome(args))
+	if err != nil {
+		fmt.Fprintf(c.term, "Cannot read %s: %v\n\n", args, err)
+		return
+	}
+	stmts := splitStatements(string(data))
+
+	prev := c.inScript
+	c.inScript = true
+	defer func() { c.inScript = prev }()
+
+	failed := 0
+	for i, stmt := range stmts {
+		echo := strings.Join(strings.Fields(stmt), " ")
+		fmt.Fprintf(c.term, "[%d/%d] %s\n", i+1, len(stmts), fitCell(echo, includeEchoWidth))
+
+		c.lastErr = nil
+		if !c.runBatchStatement(stmt) {
+			fmt.Fprintf(c.term, "Stopped at statement %d of %d.\n\n", i+1, len(stmts))
+			return
+		}
+		if c.lastErr != nil {
+			failed++
+			if !cont {
+				fmt.Fprintf(c.term, "Stopped at statement %d of %d. Use \\i --continue to keep going after errors.\n\n",
+					i+1, len(stmts))
+				return
+			}
+		}
+	}
+
+	fmt.Fprintf(c.term, "Executed %d statements from %s", len(stmts), args)
+	if failed > 0 {
+		fmt.Fprintf(c.term, ", %d failed", failed)
+	}
+	fmt.Fprintf(c.term, ".\n\n")
+}
+
+// runStatement 执行一条完整输入（特殊命令或 SQL）
+func (c *CLI) runStatement(stmt string) {
+	stmt = strings.TrimSuffix(strings.TrimSpace(stmt), ";")
+	if stmt == "" {
+		return
+	}
+	if c.handleSpecialCommand(stmt) {
+		return
+	}
+	c.executeSQL(stmt)
+}
+
+// expandHome 展开路径开头的 ~
+func expandHome(path string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, path[1:])
+		}
+	}
+	return path
+}
@@ -0,0 +1,101 @@
+package clickhouse
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+)
+
+func TestReadScriptSplitsStatements(t *testing.T) {
+	script := strings.Join([]string{
+		"-- header comment",
+		"SELECT 1; SELECT 2;",
+		"SELECT 3; -- note",
+		"\\timing",
+		"SELECT 'a;",
+		"b;' AS s;",
+		"SELECT",
+		"  4",
+		";",
+		"SELECT 5",
+	}, "\n")
+
+	var got []string
+	err := readScript(strings.NewReader(script), func(stmt string) error {
+		got = append(got, stmt)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"SELECT 1", "SELECT 2", "SELECT 3", "\\timing", "SELECT 'a;\nb;' AS s", "SELECT\n  4", "SELECT 5"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("readScript statements = %q, want %q", got, want)
+	}
+}
+
+func TestReadScriptStopsOnError(t *testing.T) {
+	stop := errors.New("stop")
+	var got []string
+	err := readScript(strings.NewReader("SELECT 1; SELECT 2; SELECT 3;"), func(stmt string) error {
+		got = append(got, stmt)
+		if len(got) == 2 {
+			return stop
+		}
+		return nil
+	})
+	if err != stop || len(got) != 2 {
+		t.Errorf("readScript = %v after %q, want stop after 2 statements", err, got)
+	}
+}
+
+// TestRunScriptAbortedBatch 语句执行中被中断时，非交互的 RunScript 不询问用户，直接返回 errBatchAborted
+func TestRunScriptAbortedBatch(t *testing.T) {
+	started := make(chan struct{}, 1)
+	done := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case started <- struct{}{}:
+		default:
+		}
+		select {
+		case <-r.Context().Done():
+		case <-done:
+		}
+	}))
+	defer srv.Close()
+	defer close(done)
+
+	host, port, _ := net.SplitHostPort(srv.Listener.Addr().String())
+	portNum, _ := strconv.Atoi(port)
+	var term bytes.Buffer
+	c := NewCLIWithConfig(&term, &Config{Host: host, Port: portNum, Protocol: protocolHTTP, InitFile: os.DevNull})
+	opts, err := c.connOptions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.db = clickhouse.OpenDB(opts)
+	defer c.db.Close()
+
+	go func() {
+		<-started
+		c.Interrupt()
+	}()
+	err = c.RunScript(context.Background(), strings.NewReader("SELECT 1;\nSELECT 2;\n"))
+	if !errors.Is(err, errBatchAborted) {
+		t.Fatalf("RunScript = %v, want errBatchAborted (output %q)", err, term.String())
+	}
+	if !strings.Contains(term.String(), "Batch aborted.") {
+		t.Errorf("output %q does not report the aborted batch", term.String())
+	}
+}
@@ -0,0 +1,39 @@
+package clickhouse
+
+import (
+	"fmt"
+	"time"
+)
+
+// clockSkewWarn 服务端与客户端时钟相差超过该值时提示
+const clockSkewWarn = time.Second
+
+// showNow 显示服务端时间、客户端时间以及两者的时钟偏差。
+// 客户端时间取请求往返的中点，以抵消网络延迟
+func (c *CLI) showNow() {
+	ctx, cancel := c.queryContext()
+	defer cancel()
+
+	var (
+		serverTime time.Time
+		timezone   string
+	)
+	sent := time.Now()
+	if err := c.db.QueryRowContext(ctx, "SELECT now64(3), timezone()").Scan(&serverTime, &timezone); err != nil {
+		c.printError(err)
+		return
+	}
+	received := time.Now()
+	rtt := received.Sub(sent)
+	clientTime := sent.Add(rtt / 2)
+	skew := serverTime.Sub(clientTime)
+
+	fmt.Fprintf(c.term, "Server time: %s (%s)\n", serverTime.Format("2006-01-02 15:04:05.000"), timezone)
+	fmt.Fprintf(c.term, "Client time: %s (%s)\n", clientTime.Format("2006-01-02 15:04:05.000"), clientTime.Location())
+	fmt.Fprintf(c.term, "Skew:        %+.3f sec (server minus client, round trip %.3f sec)\n",
+		skew.Seconds(), rtt.Seconds())
+	if skew.Abs() > clockSkewWarn+rtt/2 {
+		fmt.Fprintf(c.term, "Warning: clocks differ by more than %s; filters like ts > now() may behave unexpectedly.\n", clockSkewWarn)
+	}
+	fmt.Fprintf(c.term, "\n")
+}
@@ -0,0 +1,219 @@
+package clickhouse
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+)
+
+// settingAssignment SET 语句中的一个 name = value
+type settingAssignment struct {
+	name  string
+	value string
+}
+
+// parseSetStatement 解析 SET a = 1, b = 'x'，不是设置赋值语句（如 SET ROLE）时返回 false
+// 字符串值会去掉引号，值为 DEFAULT 时表示恢复默认
+func parseSetStatement(sqlStr string) ([]settingAssignment, bool) {
+	tokens := significantTokens(scanSQL(sqlStr))
+	if len(tokens) < 4 || !tokens[0].isKeyword("SET") {
+		return nil, false
+	}
+
+	var assignments []settingAssignment
+	i := 1
+	for i < len(tokens) {
+		if i+2 >= len(tokens) || tokens[i].kind != tokenWord || tokens[i+1].text != "=" {
+			return nil, false
+		}
+		name := tokens[i].text
+
+		start := i + 2
+		end := start
+		for end < len(tokens) && !(tokens[end].text == "," && tokens[end].depth == 0) {
+			end++
+		}
+
+		value := sqlStr[tokens[start].pos:tokens[end-1].end]
+		if end-start == 1 && tokens[start].kind == tokenString {
+			value = unquoteString(value)
+		}
+		assignments = append(assignments, settingAssignment{name: name, value: value})
+		i = end + 1
+	}
+	return assignments, len(assignments) > 0
+}
+
+// unquoteString 去掉 SQL 字符串字面量的引号并还原转义
+func unquoteString(s string) string {
+	if len(s) >= 2 && s[0] == '\'' && s[len(s)-1] == '\'' {
+		s = s[1 : len(s)-1]
+	}
+	return strings.NewReplacer(`''`, `'`, `\'`, `'`, `\\`, `\`).Replace(s)
+}
+
+// executeSet 执行 SET 语句，成功后记录为会话设置，之后每条查询都会携带
+// 连接池中的连接不共享会话状态，所以设置必须随查询发送而不能只在服务端执行一次
+func (c *CLI) executeSet(ctx context.Context, sqlStr string, assignments []settingAssignment, startTime time.Time) {
+	err := c.retryDisconnected(func() error {
+		_, err := c.db.ExecContext(ctx, sqlStr)
+		return err
+	})()
+	if err != nil {
+		c.printError(err)
+		return
+	}
+
+	for _, a := range assignments {
+		if strings.EqualFold(a.value, "DEFAULT") {
+			delete(c.settings, a.name)
+		} else {
+			c.settings[a.name] = a.value
+		}
+	}
+
+	if c.jsonOutput() {
+		c.writeAffectedEnvelope(0, startTime)
+		return
+	}
+	fmt.Fprintf(c.term, "Ok.")
+	if c.timingEnabled {
+		fmt.Fprintf(c.term, " Elapsed: %.3f sec.", time.Since(startTime).Seconds())
+	}
+	fmt.Fprintf(c.term, "\n\n")
+}
+
+// sessionSettings 返回会话设置的副本；驱动会修改传入的 Settings，不能直接共享
+func (c *CLI) sessionSettings() clickhouse.Settings {
+	settings := make(clickhouse.Settings, len(c.settings))
+	for k, v := range c.settings {
+		settings[k] = v
+	}
+	return settings
+}
+
+// showSessionSettings 处理 \settings：列出本会话用 SET 设置、随每条查询发送的设置
+func (c *CLI) showSessionSettings() {
+	if len(c.settings) == 0 {
+		fmt.Fprintf(c.term, "No session settings. Use SET name = value to add one.\n\n")
+		return
+	}
+
+	names := make([]string, 0, len(c.settings))
+	for name := range c.settings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	rs := &resultSet{cols: []string{"name", "value"}}
+	for _, name := range names {
+		rs.rows = append(rs.rows, []interface{}{name, c.settings[name]})
+	}
+	c.displayTable(c.term, rs)
+	fmt.Fprintf(c.term, "%d settings applied to every query. Use \\unset <name>|all to remove.\n\n", len(names))
+}
+
+// unsetSettings 处理 \unset <name> [name ...]|all：移除会话设置，之后的查询恢复服务端的值
+func (c *CLI) unsetSettings(args string) {
+	names := strings.FieldsFunc(args, func(r rune) bool { return r == ',' || r == ' ' })
+	if len(names) == 0 {
+		fmt.Fprintf(c.term, "Usage: \\unset <name> [name ...]|all\n")
+		return
+	}
+	if len(names) == 1 && strings.EqualFold(names[0], "all") {
+		n := len(c.settings)
+		c.settings = make(map[string]string)
+		fmt.Fprintf(c.term, "Removed %d session settings.\n", n)
+		return
+	}
+
+	for _, name := range names {
+		if _, ok := c.settings[name]; !ok {
+			fmt.Fprintf(c.term, "%s is not a session setting.\n", name)
+			continue
+		}
+		delete(c.settings, name)
+		fmt.Fprintf(c.term, "Removed %s.\n", name)
+	}
+}
+
+// settingsDiff 对比显示与默认值不同的设置，区分服务端（用户配置）和本会话 SET 的来源
+func (c *CLI) settingsDiff() {
+	names := make([]string, 0, len(c.settings))
+	for name := range c.settings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	args := make([]interface{}, len(names))
+	for i, name := range names {
+		args[i] = name
+	}
+	query := "SELECT name, `default`, value FROM system.settings WHERE changed = 1"
+	if len(names) > 0 {
+		query += " OR name IN (" + strings.TrimSuffix(strings.Repeat("?, ", len(names)), ", ") + ")"
+	}
+
+	rs, err := c.runQuery(query+" ORDER BY name", args...)
+	if err != nil {
+		c.printError(err)
+		return
+	}
+	if len(rs.rows) == 0 {
+		fmt.Fprintf(c.term, "All settings are at their defaults.\n\n")
+		return
+	}
+
+	diff := &resultSet{cols: []string{"setting", "default", "current", "source"}}
+	sessionCount := 0
+	for _, row := range rs.rows {
+		name := formatValue(row[0])
+		source := "server"
+		if _, ok := c.settings[name]; ok {
+			source = "session"
+			sessionCount++
+		}
+		diff.rows = append(diff.rows, []interface{}{name, row[1], row[2], source})
+	}
+
+	c.displayTable(c.term, diff)
+	fmt.Fprintf(c.term, "%d settings changed (%d from server/profile, %d from SET in this session).\n\n",
+		len(diff.rows), len(diff.rows)-sessionCount, sessionCount)
+}
+
+// restoreSession 换到新连接后恢复本会话状态：SET 设置和 \param 参数随每条查询发送，
+// 新服务端不认识的设置会让之后的每条查询失败，因此按 schemaSettings 移除并提示，其余的继续生效并提示数量
+func (c *CLI) restoreSession() {
+	if len(c.settings) > 0 {
+		// 加载设置列表的查询本身不能携带可能不被认识的会话设置
+		saved := c.settings
+		c.settings = nil
+		known, err := c.schemaSettings()
+		c.settings = saved
+
+		if err == nil {
+			supported := make(map[string]bool, len(known))
+			for _, s := range known {
+				supported[s.name] = true
+			}
+			names := make([]string, 0, len(c.settings))
+			for name := range c.settings {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				if !supported[name] && !strings.HasPrefix(name, "custom_") {
+					delete(c.settings, name)
+					fmt.Fprintf(c.term, "Session setting %s is not known to this server and was removed.\n", name)
+				}
+			}
+		}
+	}
+	if len(c.settings) > 0 || len(c.queryParams) > 0 {
+		fmt.Fprintf(c.term, "Kept %d session settings and %d query parameters.\n", len(c.settings), len(c.queryParams))
+	}
+}
@@ -0,0 +1,29 @@
+package clickhouse
+
+import "fmt"
+
+// listTables 处理 \dt [database]：展开为 SHOW TABLES，未指定库时列出当前库的表
+func (c *CLI) listTables(args string) {
+	database := args
+	if database == "" {
+		database = c.database
+	}
+	if database == "" {
+		c.executeSQL("SHOW TABLES")
+		return
+	}
+	c.executeSQL("SHOW TABLES FROM " + quoteIdentifier(database))
+}
+
+// describeShortcut 处理 \d <table> 和 \d+ <table>：分别展开为 DESCRIBE TABLE 和 SHOW CREATE TABLE
+func (c *CLI) describeShortcut(name, table string) {
+	if table == "" {
+		fmt.Fprintf(c.term, "Usage: %s <table>\n", name)
+		return
+	}
+	if name == "\\d+" {
+		c.executeSQL("SHOW CREATE TABLE " + table)
+		return
+	}
+	c.executeSQL("DESCRIBE TABLE " + table)
+}
@@ -0,0 +1,243 @@
+package clickhouse
+
+import (
+	"strings"
+	"unicode"
+)
+
+// tokenKind SQL 词法单元类型
+type tokenKind int
+
+const (
+	tokenWord    tokenKind = iota // 关键字或标识符
+	tokenNumber                   // 数字
+	tokenString                   // 'string'
+	tokenQuoted                   // `ident` 或 "ident"
+	tokenComment                  // -- 或 /* */ 注释
+	tokenPunct                    // 其他符号
+)
+
+// sqlToken SQL 词法单元
+type sqlToken struct {
+	kind  tokenKind
+	text  string
+	pos   int  // 在原始 SQL 中的起始字节偏移
+	end   int  // 在原始 SQL 中的结束字节偏移
+	depth int  // 括号嵌套深度
+	open  bool // 字符串、引用标识符或块注释直到输入结束仍未闭合
+}
+
+// isKeyword 判断是否是指定关键字（不区分大小写）
+func (t sqlToken) isKeyword(kw string) bool {
+	return t.kind == tokenWord && strings.EqualFold(t.text, kw)
+}
+
+// scanSQL 将 SQL 切分为词法单元，识别字符串、引用标识符和注释
+func scanSQL(s string) []sqlToken {
+	var tokens []sqlToken
+	depth := 0
+	i := 0
+
+	for i < len(s) {
+		ch := s[i]
+		start := i
+
+		switch {
+		case ch == ' ' || ch == '\t' || ch == '\n' || ch == '\r':
+			i++
+			continue
+		case ch == '-' && i+1 < len(s) && s[i+1] == '-':
+			for i < len(s) && s[i] != '\n' {
+				i++
+			}
+			tokens = append(tokens, sqlToken{kind: tokenComment, text: s[start:i], pos: start, end: i, depth: depth})
+			continue
+		case ch == '/' && i+1 < len(s) && s[i+1] == '*':
+			i += 2
+			for i < len(s) && !(s[i] == '*' && i+1 < len(s) && s[i+1] == '/') {
+				i++
+			}
+			open := i >= len(s)
+			i = min(i+2, len(s))
+			tokens = append(tokens, sqlToken{kind: tokenComment, text: s[start:i], pos: start, end: i, depth: depth, open: open})
+			continue
+		case ch == '\'' || ch == '`' || ch == '"':
+			var closed bool
+			i, closed = scanQuoted(s, i)
+			kind := tokenQuoted
+			if ch == '\'' {
+				kind = tokenString
+			}
+			tokens = append(tokens, sqlToken{kind: kind, text: s[start:i], pos: start, end: i, depth: depth, open: !closed})
+			continue
+		case ch >= '0' && ch <= '9':
+			for i < len(s) && (isWordByte(s[i]) || s[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, sqlToken{kind: tokenNumber, text: s[start:i], pos: start, end: i, depth: depth})
+			continue
+		case isWordByte(ch) || ch >= 0x80:
+			for i < len(s) && (isWordByte(s[i]) || s[i] >= 0x80) {
+				i++
+			}
+			tokens = append(tokens, sqlToken{kind: tokenWord, text: s[start:i], pos: start, end: i, depth: depth})
+			continue
+		}
+
+		i++
+		if ch == ')' && depth > 0 {
+			depth--
+		}
+		tokens = append(tokens, sqlToken{kind: tokenPunct, text: s[start:i], pos: start, end: i, depth: depth})
+		if ch == '(' {
+			depth++
+		}
+	}
+
+	return tokens
+}
+
+// scanQuoted 跳过从 i 开始的引用串，返回结束位置及是否找到闭合引号（支持反斜杠和双写转义）
+func scanQuoted(s string, i int) (int, bool) {
+	quote := s[i]
+	i++
+	for i < len(s) {
+		switch s[i] {
+		case '\\':
+			i += 2
+			continue
+		case quote:
+			if i+1 < len(s) && s[i+1] == quote {
+				i += 2
+				continue
+			}
+			return i + 1, true
+		}
+		i++
+	}
+	return len(s), false
+}
+
+// isWordByte 判断是否是标识符字符
+func isWordByte(b byte) bool {
+	return b == '_' || b == '$' || unicode.IsLetter(rune(b)) || unicode.IsDigit(rune(b))
+}
+
+// significantTokens 过滤掉注释
+func significantTokens(tokens []sqlToken) []sqlToken {
+	result := make([]sqlToken, 0, len(tokens))
+	for _, t := range tokens {
+		if t.kind != tokenComment {
+			result = append(result, t)
+		}
+	}
+	return result
+}
+
+// hasTopLevelKeywords 判断语句顶层是否包含连续的关键字序列（如 ORDER BY）
+func hasTopLevelKeywords(sqlStr string, keywords ...string) bool {
+	tokens := significantTokens(scanSQL(sqlStr))
+	for i := range tokens {
+		if tokens[i].depth != 0 || i+len(keywords) > len(tokens) {
+			continue
+		}
+		matched := true
+		for j, kw := range keywords {
+			if !tokens[i+j].isKeyword(kw) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+// firstKeyword 返回语句的第一个关键字（跳过注释和左括号），大写形式
+func firstKeyword(sqlStr string) string {
+	for _, t := range scanSQL(sqlStr) {
+		if t.kind == tokenWord {
+			return strings.ToUpper(t.text)
+		}
+		if t.kind != tokenComment && t.text != "(" {
+			return ""
+		}
+	}
+	return ""
+}
+
+// statementVerbs 可以出现在 WITH 子句之后的语句动词
+var statementVerbs = map[string]bool{
+	"SELECT": true, "INSERT": true, "ALTER": true, "DELETE": true, "UPDATE": true,
+	"CREATE": true, "OPTIMIZE": true, "EXPLAIN": true,
+}
+
+// statementVerb 返回语句实际的动词：跳过注释和左括号，以 WITH 开头时跳过 CTE 定义，
+// 返回 WITH 子句之后同一层级的第一个语句动词；找不到时返回 WITH
+func statementVerb(sqlStr string) string {
+	tokens := significantTokens(scanSQL(sqlStr))
+	for i, t := range tokens {
+		if t.text == "(" {
+			continue
+		}
+		if !t.isKeyword("WITH") {
+			return firstKeyword(sqlStr)
+		}
+		for _, next := range tokens[i+1:] {
+			if next.depth == t.depth && next.kind == tokenWord && statementVerbs[strings.ToUpper(next.text)] {
+				return strings.ToUpper(next.text)
+			}
+		}
+		return "WITH"
+	}
+	return ""
+}
+
+// statementComplete 判断输入是否已以分号结束：字符串和注释中的分号不算，
+// 分号之后只有注释时也算结束；未闭合的字符串或块注释（跨多行）表示输入尚未结束
+func statementComplete(s string) bool {
+	tokens := scanSQL(s)
+	if len(tokens) > 0 && tokens[len(tokens)-1].open {
+		return false
+	}
+	significant := significantTokens(tokens)
+	return len(significant) > 0 && significant[len(significant)-1].text == ";"
+}
+
+// splitStatements 按分号切分多条语句，字符串、引用标识符和注释中的分号不参与切分；
+// 语句开头以反斜杠开始的一行视为元命令，单独成为一条。返回的语句不含结尾分号，只含注释的片段被丢弃
+func splitStatements(s string) []string {
+	var stmts []string
+	add := func(stmt string) {
+		stmt = strings.TrimSpace(stmt)
+		if len(significantTokens(scanSQL(stmt))) > 0 {
+			stmts = append(stmts, stmt)
+		}
+	}
+
+	start, empty := 0, true
+	for _, t := range scanSQL(s) {
+		if t.pos < start {
+			continue
+		}
+		switch {
+		case t.kind == tokenComment:
+		case empty && t.text == "\\":
+			end := strings.IndexByte(s[t.pos:], '\n')
+			if end < 0 {
+				end = len(s) - t.pos
+			}
+			add(s[t.pos : t.pos+end])
+			start = t.pos + end
+		case t.kind == tokenPunct && t.text == ";":
+			add(s[start:t.pos])
+			start, empty = t.end, true
+		default:
+			empty = false
+		}
+	}
+	add(s[start:])
+	return stmts
+}
@@ -0,0 +1,36 @@
+package clickhouse
+
+import "testing"
+
+func TestStatementVerb(t *testing.T) {
+	tests := []struct {
+		sql   string
+		verb  string
+		query bool
+	}{
+		{"SELECT 1", "SELECT", true},
+		{"select 1", "SELECT", true},
+		{"WITH x AS (SELECT 1) SELECT * FROM x", "SELECT", true},
+		{"WITH 1 AS a, (SELECT max(id) FROM t) AS m SELECT a, m", "SELECT", true},
+		{"WITH x AS (SELECT 1) INSERT INTO t SELECT * FROM x", "INSERT", false},
+		{"WITH x AS (SELECT 1)", "WITH", true},
+		{"-- leading comment\nSELECT 1", "SELECT", true},
+		{"/* block */ INSERT INTO t VALUES (1)", "INSERT", false},
+		{"(SELECT 1) UNION ALL (SELECT 2)", "SELECT", true},
+		{"((WITH 1 AS a SELECT a))", "SELECT", true},
+		{"INSERT INTO t SELECT 1", "INSERT", false},
+		{"CREATE TABLE t ENGINE = Memory AS SELECT 1", "CREATE", false},
+		{"EXPLAIN SELECT 1", "EXPLAIN", true},
+		{"DESCRIBE TABLE t", "DESCRIBE", true},
+		{"ALTER TABLE t DELETE WHERE 1", "ALTER", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		if got := statementVerb(tt.sql); got != tt.verb {
+			t.Errorf("statementVerb(%q) = %q, want %q", tt.sql, got, tt.verb)
+		}
+		if got := isQuery(tt.sql); got != tt.query {
+			t.Errorf("isQuery(%q) = %v, want %v", tt.sql, got, tt.query)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package clickhouse
+
+import (
+	"bufio"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// streamThreshold \maxrows 为 0（不限制）或超过该值时，表格边读取边输出，不再缓冲全部结果
+const streamThreshold = 10000
+
+// useStream 当前查询结果是否以流式表格输出：只用于普通表格，
+// \grep、\diff-result、JSON 输出、竖排/转置、PrettyPaged 和非表格格式、分页器仍需要完整结果
+func (c *CLI) useStream(sqlStr string) bool {
+	if c.maxRows > 0 && c.maxRows <= streamThreshold {
+		return false
+	}
+	return c.pager == "" && c.grep == nil && c.diffBase == nil && !c.jsonOutput() &&
+		!c.verticalMode && !c.transposeMode && c.streamableFormat() && c.streamableStatement(sqlStr)
+}
+
+// streamableStatement 语句的结果能否边读取边输出：EXPLAIN、EXISTS、DESCRIBE 使用专门的布局，
+// \collapse 需要比较相邻行，这些结果都按完整结果渲染
+func (c *CLI) streamableStatement(sqlStr string) bool {
+	switch statementVerb(sqlStr) {
+	case "EXPLAIN", "EXISTS", "DESC", "DESCRIBE":
+		return false
+	}
+	return !c.collapse
+}
+
+// streamTable 边读取边输出表格：列宽由前 pagerSampleRows 行确定，之后的行按该宽度截断，
+// 内存占用与结果大小无关；\maxrows 不为 0 时读到该行数为止
+func (c *CLI) streamTable(rows *sql.Rows, sqlStr string, startTime time.Time) {
+	count, quit, err := c.streamRows(bufio.NewWriter(c.term), rows, c.maxRows)
+	c.lastRowCount = count
+	c.lastResult = nil
+
+	switch {
+	case err != nil:
+		c.printError(err)
+		return
+	case quit:
+		fmt.Fprintf(c.term, "Query was cancelled after %d rows.\n\n", count)
+		return
+	}
+	if c.maxRows > 0 && count == c.maxRows && rows.Next() {
+		fmt.Fprintf(c.term, "Showing first %d of %d+ rows (\\maxrows to change the limit).\n", count, count+1)
+	}
+	c.printFooter(c.term, count, startTime)
+	c.trackPage(sqlStr)
+	if c.normalized {
+		c.showNormalized(c.lastQueryID)
+	}
+}
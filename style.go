@@ -0,0 +1,59 @@
+package clickhouse
+
+// tableStyle 表格输出的边框样式
+type tableStyle struct {
+	sep         string    // 列分隔符
+	left, right string    // 每行的左右边框
+	top         *ruleLine // 表头上方的横线，nil 表示不输出
+	mid         *ruleLine // 表头与数据之间的横线
+	bottom      *ruleLine // 表格末尾的横线
+	color       bool      // 是否输出 ANSI 颜色，由 \color 决定
+	null        string    // NULL 的显示文本，开启颜色时用于识别 NULL 单元格
+	wrap        bool      // 超出列宽的单元格是否折成多行，由 \wrap 决定
+}
+
+// ruleLine 横线的组成：左端、填充、列交叉处和右端；全部为空时输出一个空行
+type ruleLine struct {
+	left, fill, cross, right string
+}
+
+// 与 clickhouse-client 的 PrettyCompact、Pretty、PrettySpace 对应的样式
+var (
+	compactStyle = &tableStyle{
+		sep: " │ ",
+		mid: &ruleLine{fill: "─", cross: "─┼─"},
+	}
+	boxStyle = &tableStyle{
+		sep:    " │ ",
+		left:   "│ ",
+		right:  " │",
+		top:    &ruleLine{"┌─", "─", "─┬─", "─┐"},
+		mid:    &ruleLine{"├─", "─", "─┼─", "─┤"},
+		bottom: &ruleLine{"└─", "─", "─┴─", "─┘"},
+	}
+	spaceStyle = &tableStyle{
+		sep: "  ",
+		mid: &ruleLine{},
+	}
+)
+
+// tableStyle 返回当前 \format 对应的表格样式，PrettyPaged 使用 PrettyCompact 样式；
+// 开启颜色或折行时返回带对应设置的副本
+func (c *CLI) tableStyle() *tableStyle {
+	st := compactStyle
+	switch c.format {
+	case formatPretty:
+		st = boxStyle
+	case formatPrettySpace:
+		st = spaceStyle
+	}
+	color := c.useColor()
+	if !color && !c.wrapCells {
+		return st
+	}
+	custom := *st
+	custom.color = color
+	custom.null = c.nullValue
+	custom.wrap = c.wrapCells
+	return &custom
+}
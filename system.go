@@ -0,0 +1,66 @@
+package clickhouse
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// systemCaches \drop-cache 可选的缓存及对应的 SYSTEM DROP 语句
+var systemCaches = map[string]string{
+	"mark":         "MARK CACHE",
+	"uncompressed": "UNCOMPRESSED CACHE",
+	"dns":          "DNS CACHE",
+	"query":        "QUERY CACHE",
+	"mmap":         "MMAP CACHE",
+	"compiled":     "COMPILED EXPRESSION CACHE",
+	"filesystem":   "FILESYSTEM CACHE",
+}
+
+// flushLogs 执行 SYSTEM FLUSH LOGS
+func (c *CLI) flushLogs() {
+	c.executeSQL("SYSTEM FLUSH LOGS")
+}
+
+// reloadConfig 执行 SYSTEM RELOAD CONFIG
+func (c *CLI) reloadConfig() {
+	c.executeSQL("SYSTEM RELOAD CONFIG")
+}
+
+// dropCache 处理 \drop-cache <name>|all，确认后执行 SYSTEM DROP ... CACHE
+func (c *CLI) dropCache(args string) {
+	names := make([]string, 0, len(systemCaches))
+	for name := range systemCaches {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	name := strings.ToLower(args)
+	var targets []string
+	switch {
+	case name == "all":
+		targets = names
+	case systemCaches[name] != "":
+		targets = []string{name}
+	default:
+		fmt.Fprintf(c.term, "Usage: \\drop-cache <%s|all>\n", strings.Join(names, "|"))
+		return
+	}
+
+	if !c.confirm(fmt.Sprintf("Drop %s cache on %s? Queries will be slower until it warms up.", name, c.addr())) {
+		fmt.Fprintf(c.term, "Cancelled.\n\n")
+		return
+	}
+	for _, target := range targets {
+		c.executeSQL("SYSTEM DROP " + systemCaches[target])
+	}
+}
+
+// syncReplica 执行 SYSTEM SYNC REPLICA，等待副本追上复制队列
+func (c *CLI) syncReplica(table string) {
+	if table == "" {
+		fmt.Fprintf(c.term, "Usage: \\sync-replica <table>\n")
+		return
+	}
+	c.executeSQL("SYSTEM SYNC REPLICA " + table)
+}
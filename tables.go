@@ -0,0 +1,87 @@
+package clickhouse
+
+import (
+	"fmt"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+)
+
+// ClickHouse 错误码
+const (
+	codeNotImplemented             = 48
+	codeTooManySimultaneousQueries = 202
+)
+
+// checkTable 执行 CHECK TABLE 并按数据分片显示检查结果
+func (c *CLI) checkTable(table string) {
+	if table == "" {
+		fmt.Fprintf(c.term, "Usage: \\check <table>\n")
+		return
+	}
+
+	ctx, cancel := c.queryContext()
+	defer cancel()
+	ctx = clickhouse.Context(ctx, clickhouse.WithSettings(clickhouse.Settings{
+		"check_query_single_value_result": 0,
+	}))
+
+	rows, err := c.db.QueryContext(ctx, "CHECK TABLE "+table)
+	if err != nil {
+		if exception, ok := serverException(err); ok && exception.Code == codeNotImplemented {
+			fmt.Fprintf(c.term, "CHECK TABLE is not supported by the engine of %s.\n\n", table)
+			return
+		}
+		c.printError(err)
+		return
+	}
+	defer rows.Close()
+
+	rs := &resultSet{cols: []string{"part_path", "is_passed", "message"}}
+	passed, failed := 0, 0
+	for rows.Next() {
+		var (
+			partPath, message string
+			isPassed          uint8
+		)
+		if err := rows.Scan(&partPath, &isPassed, &message); err != nil {
+			c.printError(err)
+			return
+		}
+
+		status := "FAILED"
+		if isPassed == 1 {
+			status = "ok"
+			passed++
+		} else {
+			failed++
+		}
+		rs.rows = append(rs.rows, []interface{}{partPath, status, message})
+	}
+	if err := rows.Err(); err != nil {
+		c.printError(err)
+		return
+	}
+
+	c.displayTable(c.term, rs)
+	fmt.Fprintf(c.term, "%d parts checked: %d passed, %d failed.\n\n", passed+failed, passed, failed)
+}
+
+// columnInfo 表字段信息
+type columnInfo struct {
+	name string
+	typ  string
+}
+
+// tableColumns 通过 DESCRIBE TABLE 获取表字段
+func (c *CLI) tableColumns(table string) ([]columnInfo, error) {
+	rs, err := c.runQuery("DESCRIBE TABLE " + table)
+	if err != nil {
+		return nil, err
+	}
+
+	cols := make([]columnInfo, 0, len(rs.rows))
+	for _, row := range rs.rows {
+		cols = append(cols, columnInfo{name: formatValue(row[0]), typ: formatValue(row[1])})
+	}
+	return cols, nil
+}
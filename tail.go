@@ -0,0 +1,160 @@
+package clickhouse
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 默认显示的行数和 -f 模式的轮询间隔
+const (
+	defaultTailRows  = 10
+	tailPollInterval = 2 * time.Second
+)
+
+// tailTable 处理 \tail [-f] <table> [ts_column] [n]：按时间列倒序取最近 n 行并按时间顺序显示
+// 未指定时间列时使用表中第一个 DateTime 列（没有则用 Date 列）；-f 持续轮询新行，回车结束
+func (c *CLI) tailTable(args string) {
+	fields := strings.Fields(args)
+	follow := false
+	if len(fields) > 0 && fields[0] == "-f" {
+		follow = true
+		fields = fields[1:]
+	}
+	if len(fields) == 0 {
+		fmt.Fprintf(c.term, "Usage: \\tail [-f] <table> [ts_column] [n]\n")
+		return
+	}
+
+	table, tsCol, n := fields[0], "", defaultTailRows
+	for _, f := range fields[1:] {
+		if v, err := strconv.Atoi(f); err == nil && v > 0 {
+			n = v
+		} else {
+			tsCol = f
+		}
+	}
+
+	if tsCol == "" {
+		var err error
+		if tsCol, err = c.detectTimeColumn(table); err != nil {
+			c.printError(err)
+			return
+		}
+		if tsCol == "" {
+			fmt.Fprintf(c.term, "No Date/DateTime column in %s. Use \\tail %s <ts_column>.\n", table, table)
+			return
+		}
+	}
+
+	query := fmt.Sprintf("SELECT * FROM %s ORDER BY %s DESC LIMIT %d", table, quoteIdentifier(tsCol), n)
+	rs, err := c.runQuery(query)
+	if err != nil {
+		c.printError(err)
+		return
+	}
+	for i, j := 0, len(rs.rows)-1; i < j; i, j = i+1, j-1 {
+		rs.rows[i], rs.rows[j] = rs.rows[j], rs.rows[i]
+	}
+
+	cells, colWidths := c.tableCells(rs)
+	if !follow {
+		c.renderTable(c.term, rs.cols, rs.types, cells, colWidths)
+		fmt.Fprintf(c.term, "Last %d rows of %s by %s.\n\n", len(rs.rows), table, tsCol)
+		return
+	}
+
+	st := c.tableStyle()
+	right := c.columnAlign(rs.cols, rs.types)
+	st.writeHeader(c.term, rs.cols, c.headerTypes(rs.types), colWidths, right)
+	for _, row := range cells {
+		st.writeRow(c.term, row, colWidths, right)
+	}
+	c.followTable(table, tsCol, rs, colWidths)
+}
+
+// followTable 轮询时间列大于已见最大值的新行并追加输出，直到用户按回车
+func (c *CLI) followTable(table, tsCol string, rs *resultSet, colWidths []int) {
+	st := c.tableStyle()
+	right := c.columnAlign(rs.cols, rs.types)
+	tsIdx := -1
+	for i, col := range rs.cols {
+		if col == tsCol {
+			tsIdx = i
+		}
+	}
+	var last interface{}
+	if tsIdx >= 0 && len(rs.rows) > 0 {
+		last = rs.rows[len(rs.rows)-1][tsIdx]
+	}
+
+	// 在后台读取一行输入作为停止信号，输出通过 reader.Print 写在提示符上方
+	stop := make(chan struct{})
+	c.reader.SetPrompt("-- following " + table + ", press Enter to stop -- ")
+	go func() {
+		c.reader.ReadLine()
+		close(stop)
+	}()
+
+	ticker := time.NewTicker(tailPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			fmt.Fprintf(c.term, "\n")
+			return
+		case <-ticker.C:
+		}
+
+		query := fmt.Sprintf("SELECT * FROM %s ORDER BY %s", table, quoteIdentifier(tsCol))
+		var args []interface{}
+		if last != nil {
+			query = fmt.Sprintf("SELECT * FROM %s WHERE %s > ? ORDER BY %s",
+				table, quoteIdentifier(tsCol), quoteIdentifier(tsCol))
+			args = append(args, last)
+		}
+		if c.maxRows > 0 {
+			query += fmt.Sprintf(" LIMIT %d", c.maxRows)
+		}
+		rows, err := c.runQuery(query, args...)
+		if err != nil {
+			c.reader.Print(fmt.Sprintf("Error: %v\n", err))
+			continue
+		}
+
+		var b strings.Builder
+		for _, vals := range rows.rows {
+			row := make([]string, len(vals))
+			for i, v := range vals {
+				row[i] = st.fit(c.formatCell(v, rows.typeOf(i)), colWidths[i])
+			}
+			st.writeRow(&b, row, colWidths, right)
+			if tsIdx >= 0 {
+				last = vals[tsIdx]
+			}
+		}
+		if b.Len() > 0 {
+			c.reader.Print(b.String())
+		}
+	}
+}
+
+// detectTimeColumn 返回表中第一个 DateTime/DateTime64 列，没有时返回第一个 Date 列
+func (c *CLI) detectTimeColumn(table string) (string, error) {
+	columns, err := c.tableColumns(table)
+	if err != nil {
+		return "", err
+	}
+	dateCol := ""
+	for _, col := range columns {
+		typ := baseType(col.typ)
+		switch {
+		case strings.HasPrefix(typ, "DateTime"):
+			return col.name, nil
+		case dateCol == "" && strings.HasPrefix(typ, "Date"):
+			dateCol = col.name
+		}
+	}
+	return dateCol, nil
+}
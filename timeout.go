@@ -0,0 +1,36 @@
+package clickhouse
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultQueryTimeout 单条语句的默认客户端超时
+const defaultQueryTimeout = 60 * time.Second
+
+// setQueryTimeout 处理 \timeout [seconds|off]：设置单条语句的客户端超时，0 或 off 表示不限制
+func (c *CLI) setQueryTimeout(args string) {
+	switch strings.ToLower(args) {
+	case "":
+		if c.queryTimeout > 0 {
+			fmt.Fprintf(c.term, "Query timeout: %s\n", c.queryTimeout)
+		} else {
+			fmt.Fprintf(c.term, "Query timeout is off.\n")
+		}
+		return
+	case "off", "0":
+		c.queryTimeout = 0
+		fmt.Fprintf(c.term, "Query timeout disabled.\n")
+		return
+	}
+
+	n, err := strconv.Atoi(args)
+	if err != nil || n < 0 {
+		fmt.Fprintf(c.term, "Usage: \\timeout <seconds>|off\n")
+		return
+	}
+	c.queryTimeout = time.Duration(n) * time.Second
+	fmt.Fprintf(c.term, "Query timeout set to %s.\n", c.queryTimeout)
+}
@@ -0,0 +1,95 @@
+package clickhouse
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// \top 默认参数
+const (
+	defaultTopRows   = 10
+	defaultTopMetric = "bytes"
+	defaultTopWindow = time.Hour
+	topQueryWidth    = 80 // 查询文本截断长度
+)
+
+// topMetrics \top 可用的排序指标及对应的 system.query_log 列
+var topMetrics = map[string]string{
+	"bytes":    "read_bytes",
+	"rows":     "read_rows",
+	"memory":   "memory_usage",
+	"duration": "query_duration_ms",
+	"result":   "result_bytes",
+}
+
+// showTopQueries 列出最近一段时间内最耗资源的已完成查询，参数顺序任意：\top [n] [metric] [window]
+func (c *CLI) showTopQueries(args string) {
+	n, metric, window := defaultTopRows, defaultTopMetric, defaultTopWindow
+	for _, f := range strings.Fields(args) {
+		if v, err := strconv.Atoi(f); err == nil && v > 0 {
+			n = v
+		} else if _, ok := topMetrics[strings.ToLower(f)]; ok {
+			metric = strings.ToLower(f)
+		} else if d, err := parseWindow(f); err == nil && d > 0 {
+			window = d
+		} else {
+			metrics := make([]string, 0, len(topMetrics))
+			for m := range topMetrics {
+				metrics = append(metrics, m)
+			}
+			sort.Strings(metrics)
+			fmt.Fprintf(c.term, "Usage: \\top [n] [%s] [window, e.g. 30m, 6h, 2d]\n", strings.Join(metrics, "|"))
+			return
+		}
+	}
+
+	ctx, cancel := c.queryContext()
+	// query_log 异步落盘，先刷新；没有 SYSTEM 权限时忽略错误
+	c.db.ExecContext(ctx, "SYSTEM FLUSH LOGS")
+	cancel()
+
+	seconds := int64(window / time.Second)
+	rs, err := c.runQuery(fmt.Sprintf(`SELECT
+    event_time,
+    user,
+    round(query_duration_ms / 1000, 2) AS duration_s,
+    formatReadableQuantity(read_rows) AS read_rows,
+    formatReadableSize(read_bytes) AS read_bytes,
+    formatReadableSize(memory_usage) AS memory,
+    formatReadableSize(result_bytes) AS result,
+    substringUTF8(replaceRegexpAll(query, '\\s+', ' '), 1, %d) AS query,
+    query_id
+FROM system.query_log
+WHERE type = 'QueryFinish'
+    AND is_initial_query
+    AND event_date >= toDate(now() - toIntervalSecond(?))
+    AND event_time >= now() - toIntervalSecond(?)
+ORDER BY %s DESC
+LIMIT ?`, topQueryWidth, topMetrics[metric]), seconds, seconds, n)
+	if err != nil {
+		c.printError(err)
+		return
+	}
+	if len(rs.rows) == 0 {
+		fmt.Fprintf(c.term, "No finished queries in the last %s.\n\n", window)
+		return
+	}
+
+	c.displayTable(c.term, rs)
+	fmt.Fprintf(c.term, "Top %d queries by %s in the last %s.\n\n", len(rs.rows), metric, window)
+}
+
+// parseWindow 解析时间窗口，在 time.ParseDuration 的基础上支持 d（天）
+func parseWindow(s string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, err
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	return time.ParseDuration(s)
+}
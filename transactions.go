@@ -0,0 +1,43 @@
+package clickhouse
+
+import (
+	"fmt"
+	"strings"
+)
+
+// isTransactionStatement 判断是否是 BEGIN/COMMIT/ROLLBACK 等事务控制语句
+func isTransactionStatement(cmdLower string) bool {
+	fields := strings.Fields(strings.TrimSuffix(cmdLower, ";"))
+	if len(fields) == 0 {
+		return false
+	}
+	switch fields[0] {
+	case "begin", "commit", "rollback":
+		return true
+	case "start":
+		return len(fields) > 1 && fields[1] == "transaction"
+	}
+	return false
+}
+
+// trackTransaction 事务语句发送到服务端并成功后记录事务状态：BEGIN 开启，COMMIT/ROLLBACK 结束
+func (c *CLI) trackTransaction(sqlStr string) {
+	lower := strings.ToLower(sqlStr)
+	if !c.allowTx || !isTransactionStatement(lower) {
+		return
+	}
+	first := strings.Fields(lower)[0]
+	c.inTransaction = first == "begin" || first == "start"
+}
+
+// explainTransactions 拦截事务控制语句，说明 ClickHouse 的语句立即生效，没有可回滚的事务
+func (c *CLI) explainTransactions(cmd string) {
+	keyword := strings.ToUpper(strings.Fields(cmd)[0])
+	fmt.Fprintf(c.term, `%s was not sent to the server.
+ClickHouse executes every statement immediately; there is no open transaction to commit or roll back,
+and statements already run (including INSERT, ALTER ... DELETE/UPDATE) cannot be undone this way.
+Experimental transactions (allow_experimental_transactions) only cover MergeTree tables and need one
+connection for the whole transaction; enable Config.AllowTransactions to pass these statements through.
+
+`, keyword)
+}
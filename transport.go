@@ -0,0 +1,77 @@
+package clickhouse
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+)
+
+// 连接协议：native 使用 TCP 原生协议，http 使用 HTTP 接口，适用于原生端口被防火墙挡住的环境
+const (
+	protocolNative = "native"
+	protocolHTTP   = "http"
+)
+
+// defaultHTTPSPort 启用 TLS 时 HTTP 接口的默认端口
+const defaultHTTPSPort = 8443
+
+// viaHTTP 是否通过 HTTP 接口连接
+func (c *CLI) viaHTTP() bool {
+	return c.protocol == protocolHTTP
+}
+
+// defaultPort 返回未配置端口时使用的端口，取决于协议和是否启用 TLS
+func (c *CLI) defaultPort() int {
+	switch {
+	case c.viaHTTP() && c.config.Secure:
+		return defaultHTTPSPort
+	case c.viaHTTP():
+		return defaultHTTPPort
+	case c.config.Secure:
+		return defaultSecurePort
+	}
+	return defaultNativePort
+}
+
+// portHint 返回连接失败时提示用户核对的端口说明
+func (c *CLI) portHint() string {
+	if c.viaHTTP() {
+		return "HTTP port (8123, or 8443 with TLS)"
+	}
+	return "native TCP port (9000, or 9440 with TLS)"
+}
+
+// httpErrorPattern 匹配 HTTP 接口返回的错误正文，如 "Code: 60. DB::Exception: Table default.t does not exist."
+var httpErrorPattern = regexp.MustCompile(`(?s)Code: (\d+)\. ([\w:]+): (.*)`)
+
+// serverException 从错误中取出服务端异常。原生协议下驱动直接返回 *clickhouse.Exception；
+// HTTP 接口下驱动只返回包含响应正文的普通错误，这里解析正文中的错误码、名称和消息，
+// 使重试、密码询问和错误输出在两种协议下行为一致
+func serverException(err error) (*clickhouse.Exception, bool) {
+	var exception *clickhouse.Exception
+	if errors.As(err, &exception) {
+		return exception, true
+	}
+	for ; err != nil; err = errors.Unwrap(err) {
+		if strings.HasPrefix(err.Error(), "clickhouse [execute]") {
+			return parseHTTPException(err.Error())
+		}
+	}
+	return nil, false
+}
+
+// parseHTTPException 解析 HTTP 接口错误正文中的错误码、名称和消息
+func parseHTTPException(body string) (*clickhouse.Exception, bool) {
+	m := httpErrorPattern.FindStringSubmatch(body)
+	if m == nil {
+		return nil, false
+	}
+	code, err := strconv.ParseInt(m[1], 10, 32)
+	if err != nil {
+		return nil, false
+	}
+	return &clickhouse.Exception{Code: int32(code), Name: m[2], Message: strings.TrimSpace(m[3])}, true
+}
@@ -0,0 +1,105 @@
+package clickhouse
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/paulmach/orb"
+)
+
+// isNestedValue 判断值是否需要以树形展示：
+// Map、命名 Tuple、JSON 对象，或包含此类结构的数组/Tuple；地理类型以 WKT 单行显示
+func isNestedValue(v interface{}) bool {
+	if _, ok := v.(orb.Geometry); ok {
+		return false
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Map:
+		return true
+	case reflect.Slice, reflect.Array:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			return false
+		}
+		for i := 0; i < rv.Len(); i++ {
+			if isContainer(rv.Index(i)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isContainer 判断反射值是否是容器类型（[]byte 除外）
+func isContainer(rv reflect.Value) bool {
+	for rv.Kind() == reflect.Interface || rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return false
+		}
+		rv = rv.Elem()
+	}
+	switch rv.Kind() {
+	case reflect.Map:
+		return true
+	case reflect.Slice, reflect.Array:
+		return rv.Type().Elem().Kind() != reflect.Uint8
+	}
+	return false
+}
+
+// writeTree 以缩进树形式输出嵌套值，子节点比父节点多缩进两格；
+// typ 为值的类型，叶子节点与表格单元格一样经 formatCell 格式化
+func (c *CLI) writeTree(w io.Writer, v interface{}, typ string, indent int) {
+	pad := strings.Repeat("  ", indent)
+	rv := reflect.ValueOf(v)
+
+	switch rv.Kind() {
+	case reflect.Map:
+		keys := rv.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+		})
+		for i, k := range keys {
+			label := fmt.Sprint(k.Interface())
+			c.writeTreeNode(w, pad, label, rv.MapIndex(k).Interface(), memberType(typ, i, label), indent)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			c.writeTreeNode(w, pad, fmt.Sprintf("[%d]", i), rv.Index(i).Interface(), memberType(typ, i, ""), indent)
+		}
+	default:
+		fmt.Fprintf(w, "%s%s\n", pad, c.limitCell(c.formatCell(v, typ)))
+	}
+}
+
+// writeTreeNode 输出一个树节点：标量值和扁平数组与键同行，容器值展开到下一级
+func (c *CLI) writeTreeNode(w io.Writer, pad, label string, v interface{}, typ string, indent int) {
+	if isNestedValue(v) {
+		fmt.Fprintf(w, "%s%s:\n", pad, label)
+		c.writeTree(w, v, typ, indent+1)
+		return
+	}
+	fmt.Fprintf(w, "%s%s: %s\n", pad, label, c.limitCell(c.formatCell(v, typ)))
+}
+
+// memberType 返回复合类型中成员的类型：Array 的元素、Map 的值，Tuple 按字段名或下标查找；无法确定时为空
+func memberType(typ string, index int, field string) string {
+	name, args := splitTypeArgs(baseType(typ))
+	switch {
+	case name == "Array" && len(args) == 1:
+		return args[0]
+	case name == "Map" && len(args) == 2:
+		return args[1]
+	case name == "Tuple":
+		for i, arg := range args {
+			argName, argType := tupleField(arg)
+			if (field != "" && argName == field) || (argName == "" && i == index) {
+				return argType
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,137 @@
+package clickhouse
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// enterValues 引导式逐行录入：按列提示输入并由服务端校验类型，\done 结束后一次性插入
+func (c *CLI) enterValues(table string) {
+	if table == "" {
+		fmt.Fprintf(c.term, "Usage: \\values <table>\n")
+		return
+	}
+
+	database, name := splitQualifiedName(table, c.database)
+	columns, err := c.schemaColumns(database, name)
+	if err == nil && len(columns) == 0 {
+		columns, err = c.tableColumns(table)
+	}
+	if err != nil {
+		c.printError(err)
+		return
+	}
+
+	nameWidth := 0
+	for _, col := range columns {
+		nameWidth = max(nameWidth, len(col.name))
+	}
+	fmt.Fprintf(c.term, "Columns of %s:\n", table)
+	for _, col := range columns {
+		fmt.Fprintf(c.term, "  %-*s  %s\n", nameWidth, col.name, col.typ)
+	}
+	fmt.Fprintf(c.term, "Enter one value per prompt. Empty = type default (NULL for Nullable), \\N = NULL,\n")
+	fmt.Fprintf(c.term, "\\done = insert the completed rows, \\cancel = discard everything.\n\n")
+
+	var records [][]string
+	for {
+		record, done, ok := c.readValuesRow(len(records)+1, columns, nameWidth)
+		if !ok {
+			fmt.Fprintf(c.term, "Cancelled. No rows inserted.\n\n")
+			return
+		}
+		if record != nil {
+			records = append(records, record)
+		}
+		if done {
+			break
+		}
+	}
+	if len(records) == 0 {
+		fmt.Fprintf(c.term, "No rows entered.\n\n")
+		return
+	}
+
+	names := make([]string, len(columns))
+	for i, col := range columns {
+		names[i] = col.name
+	}
+
+	startTime := time.Now()
+	inserted, rejected, err := c.insertValues(table, names, records)
+	if err != nil {
+		c.printError(err)
+		return
+	}
+	fmt.Fprintf(c.term, "Ok. %d rows inserted into %s.", inserted, table)
+	if len(rejected) > 0 {
+		fmt.Fprintf(c.term, " %d rows rejected.", len(rejected))
+	}
+	if c.timingEnabled {
+		fmt.Fprintf(c.term, " Elapsed: %.3f sec.", time.Since(startTime).Seconds())
+	}
+	fmt.Fprintf(c.term, "\n\n")
+}
+
+// readValuesRow 读取一行的各列值。返回完整的行（\done 时未完成的行为 nil）、
+// 是否结束录入，以及是否继续（\cancel、Ctrl-C 或 EOF 时为 false）
+func (c *CLI) readValuesRow(rowNum int, columns []columnInfo, nameWidth int) ([]string, bool, bool) {
+	fmt.Fprintf(c.term, "Row %d:\n", rowNum)
+	record := make([]string, 0, len(columns))
+	for i := 0; i < len(columns); {
+		col := columns[i]
+		c.reader.SetPrompt(fmt.Sprintf("  %-*s (%s): ", nameWidth, col.name, col.typ))
+		line, err := c.reader.ReadLine()
+		if err != nil {
+			return nil, false, false
+		}
+
+		switch strings.TrimSpace(line) {
+		case `\cancel`:
+			return nil, false, false
+		case `\done`:
+			if len(record) > 0 {
+				fmt.Fprintf(c.term, "Incomplete row %d discarded.\n", rowNum)
+			}
+			return nil, true, true
+		}
+
+		value, err := c.checkValue(line, col.typ)
+		if err != nil {
+			fmt.Fprintf(c.term, "  Invalid %s: %v\n", col.typ, err)
+			continue
+		}
+		record = append(record, value)
+		i++
+	}
+	return record, false, true
+}
+
+// checkValue 由服务端按列类型解析输入，返回规范化后的文本值；\N 表示 NULL
+func (c *CLI) checkValue(input, typ string) (string, error) {
+	if input == `\N` || (input == "" && isNullableType(typ)) {
+		if !isNullableType(typ) {
+			return "", fmt.Errorf("column is not Nullable")
+		}
+		return `\N`, nil
+	}
+
+	query, args := "SELECT toString(CAST(?, ?))", []interface{}{input, typ}
+	if input == "" {
+		query, args = "SELECT toString(defaultValueOfTypeName(?))", []interface{}{typ}
+	}
+
+	ctx, cancel := c.queryContext()
+	defer cancel()
+	var value string
+	if err := c.db.QueryRowContext(ctx, query, args...).Scan(&value); err != nil {
+		return "", err
+	}
+	return value, nil
+}
+
+// isNullableType 判断类型是否可为 NULL（包括 LowCardinality(Nullable(...))）
+func isNullableType(typ string) bool {
+	return strings.HasPrefix(strings.TrimPrefix(typ, "LowCardinality("), "Nullable(")
+}
@@ -0,0 +1,47 @@
+package clickhouse
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// watchQuery 处理 \watch <seconds> <query>：每隔 seconds 秒清屏并重新执行查询，
+// 每次输出时间戳标题，结果按当前的表格/竖排、计时等设置显示，Ctrl-C 结束
+func (c *CLI) watchQuery(args string) {
+	fields := strings.Fields(args)
+	if len(fields) < 2 {
+		fmt.Fprintf(c.term, "Usage: \\watch <seconds> <query>\n")
+		return
+	}
+	secs, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil || secs <= 0 {
+		fmt.Fprintf(c.term, "Invalid interval '%s': expected a positive number of seconds.\n", fields[0])
+		return
+	}
+	interval := time.Duration(secs * float64(time.Second))
+	query := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(args), fields[0]))
+	query = strings.TrimSpace(strings.TrimSuffix(query, ";"))
+
+	// 每次刷新都会清屏，分页器只会打断刷新，监视期间暂时关闭
+	pager := c.pager
+	c.pager = ""
+	defer func() { c.pager = pager }()
+
+	c.runInterruptible(func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			fmt.Fprintf(c.term, "\033[2J\033[H")
+			fmt.Fprintf(c.term, "Every %s: %s    %s\n\n", interval, fitCell(query, 60), time.Now().Format("2006-01-02 15:04:05"))
+			c.executeSQL(query)
+			select {
+			case <-c.stmtCtx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	})
+	fmt.Fprintf(c.term, "Watch stopped.\n\n")
+}
@@ -0,0 +1,42 @@
+package clickhouse
+
+import "fmt"
+
+// showWhoami 显示当前用户、启用的角色、当前数据库以及是否拥有 ALL ON *.* 权限
+func (c *CLI) showWhoami() {
+	ctx, cancel := c.queryContext()
+	defer cancel()
+
+	var user, database, roles string
+	err := c.db.QueryRowContext(ctx, `SELECT
+    currentUser(),
+    currentDatabase(),
+    (SELECT arrayStringConcat(groupArray(role_name), ', ') FROM system.current_roles)`).
+		Scan(&user, &database, &roles)
+	if err != nil {
+		c.printError(err)
+		return
+	}
+	if roles == "" {
+		roles = "(none)"
+	}
+
+	admin := "unknown (cannot read system.grants)"
+	var grants uint64
+	err = c.db.QueryRowContext(ctx, `SELECT count()
+FROM system.grants
+WHERE (user_name = currentUser() OR role_name IN (SELECT role_name FROM system.enabled_roles))
+    AND access_type = 'ALL' AND database IS NULL AND NOT is_partial_revoke`).Scan(&grants)
+	if err == nil {
+		admin = "no"
+		if grants > 0 {
+			admin = "yes (ALL ON *.*)"
+		}
+	}
+
+	fmt.Fprintf(c.term, "User:     %s\n", user)
+	fmt.Fprintf(c.term, "Roles:    %s\n", roles)
+	fmt.Fprintf(c.term, "Database: %s\n", database)
+	fmt.Fprintf(c.term, "Admin:    %s\n", admin)
+	fmt.Fprintf(c.term, "Server:   %s\n\n", c.addr())
+}
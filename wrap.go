@@ -0,0 +1,118 @@
+package clickhouse
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// defaultWidthLimit 表格列宽的默认上限，超出的值被截断或折行
+const defaultWidthLimit = 50
+
+// setWidthLimit 处理 \widthlimit [N]：设置表格列宽上限，0 表示不限制、完整显示所有值
+func (c *CLI) setWidthLimit(args string) {
+	if args == "" {
+		if c.widthLimit == 0 {
+			fmt.Fprintf(c.term, "Column width is not limited.\n")
+		} else {
+			fmt.Fprintf(c.term, "Column width limit: %d\n", c.widthLimit)
+		}
+		return
+	}
+	n, err := strconv.Atoi(args)
+	if err != nil || n < 0 {
+		fmt.Fprintf(c.term, "Usage: \\widthlimit <n> (0 = no limit)\n")
+		return
+	}
+	c.widthLimit = n
+	if n == 0 {
+		fmt.Fprintf(c.term, "Column width limit removed, values are shown in full.\n")
+	} else {
+		fmt.Fprintf(c.term, "Column width limit set to %d.\n", n)
+	}
+}
+
+// setWrap 处理 \wrap [on|off]：超出列宽的值折成多行显示，而不是截断为 ...
+func (c *CLI) setWrap(args string) {
+	switch strings.ToLower(args) {
+	case "":
+		c.wrapCells = !c.wrapCells
+	case "on":
+		c.wrapCells = true
+	case "off":
+		c.wrapCells = false
+	default:
+		fmt.Fprintf(c.term, "Usage: \\wrap [on|off]\n")
+		return
+	}
+	if c.wrapCells {
+		fmt.Fprintf(c.term, "Long values wrap within their column.\n")
+	} else {
+		fmt.Fprintf(c.term, "Long values are truncated at the column width limit.\n")
+	}
+}
+
+// cellWidth 返回单元格占用的列宽（字符数，与 padCell 的填充一致）：折行模式下为最长一行的长度
+func cellWidth(s string, wrap bool) int {
+	if !wrap {
+		return utf8.RuneCountInString(s)
+	}
+	width := 0
+	for _, line := range strings.Split(s, "\n") {
+		width = max(width, utf8.RuneCountInString(line))
+	}
+	return width
+}
+
+// fit 按列宽截断单元格；折行模式下原样返回，由 writeCells 拆成多行
+func (st *tableStyle) fit(s string, width int) string {
+	if st.wrap {
+		return s
+	}
+	return fitCell(s, width)
+}
+
+// wrapRow 将一行单元格按列宽拆成多个输出行，较矮的单元格在后续行中留空
+func wrapRow(row []string, colWidths []int) [][]string {
+	cells := make([][]string, len(row))
+	height := 1
+	for i, val := range row {
+		cells[i] = wrapCell(val, colWidths[i])
+		height = max(height, len(cells[i]))
+	}
+	lines := make([][]string, height)
+	for n := range lines {
+		lines[n] = make([]string, len(row))
+		for i, parts := range cells {
+			if n < len(parts) {
+				lines[n][i] = parts[n]
+			}
+		}
+	}
+	return lines
+}
+
+// wrapCell 按换行符和列宽（字符数）拆分单元格，不会从多字节字符中间断开
+func wrapCell(s string, width int) []string {
+	var parts []string
+	for _, line := range strings.Split(s, "\n") {
+		for width > 0 && utf8.RuneCountInString(line) > width {
+			cut := runeOffset(line, width)
+			parts = append(parts, line[:cut])
+			line = line[cut:]
+		}
+		parts = append(parts, line)
+	}
+	return parts
+}
+
+// runeOffset 返回 s 前 n 个字符的字节长度，用于按字符截断而不破坏多字节字符
+func runeOffset(s string, n int) int {
+	off := 0
+	for ; n > 0 && off < len(s); n-- {
+		_, size := utf8.DecodeRuneInString(s[off:])
+		off += size
+	}
+	return off
+}